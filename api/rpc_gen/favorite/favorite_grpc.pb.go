@@ -19,13 +19,34 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	FavoriteService_FavoriteAction_FullMethodName     = "/favorite.FavoriteService/FavoriteAction"
-	FavoriteService_FavoriteList_FullMethodName       = "/favorite.FavoriteService/FavoriteList"
-	FavoriteService_IsFavorite_FullMethodName         = "/favorite.FavoriteService/IsFavorite"
-	FavoriteService_UserFavoriteCount_FullMethodName  = "/favorite.FavoriteService/UserFavoriteCount"
-	FavoriteService_UserFavoritedCount_FullMethodName = "/favorite.FavoriteService/UserFavoritedCount"
-	FavoriteService_FavoriteCount_FullMethodName      = "/favorite.FavoriteService/FavoriteCount"
-	FavoriteService_BizFavoriteUser_FullMethodName    = "/favorite.FavoriteService/BizFavoriteUser"
+	FavoriteService_FavoriteAction_FullMethodName        = "/favorite.FavoriteService/FavoriteAction"
+	FavoriteService_FavoriteList_FullMethodName          = "/favorite.FavoriteService/FavoriteList"
+	FavoriteService_IsFavorite_FullMethodName            = "/favorite.FavoriteService/IsFavorite"
+	FavoriteService_UserFavoriteCount_FullMethodName     = "/favorite.FavoriteService/UserFavoriteCount"
+	FavoriteService_UserFavoritedCount_FullMethodName    = "/favorite.FavoriteService/UserFavoritedCount"
+	FavoriteService_FavoriteCount_FullMethodName         = "/favorite.FavoriteService/FavoriteCount"
+	FavoriteService_BizFavoriteUser_FullMethodName       = "/favorite.FavoriteService/BizFavoriteUser"
+	FavoriteService_ListFlaggedFavorites_FullMethodName  = "/favorite.FavoriteService/ListFlaggedFavorites"
+	FavoriteService_PurgeFlaggedFavorites_FullMethodName = "/favorite.FavoriteService/PurgeFlaggedFavorites"
+	FavoriteService_GetFavoritePrivacy_FullMethodName    = "/favorite.FavoriteService/GetFavoritePrivacy"
+	FavoriteService_UpdateFavoritePrivacy_FullMethodName = "/favorite.FavoriteService/UpdateFavoritePrivacy"
+	FavoriteService_EraseUserFavorites_FullMethodName    = "/favorite.FavoriteService/EraseUserFavorites"
+	FavoriteService_GetEraseStatus_FullMethodName        = "/favorite.FavoriteService/GetEraseStatus"
+	FavoriteService_PurgeContent_FullMethodName          = "/favorite.FavoriteService/PurgeContent"
+	FavoriteService_UnFavoriteHistory_FullMethodName     = "/favorite.FavoriteService/UnFavoriteHistory"
+	FavoriteService_QueryActionLogs_FullMethodName       = "/favorite.FavoriteService/QueryActionLogs"
+	FavoriteService_FavoriteStateAt_FullMethodName       = "/favorite.FavoriteService/FavoriteStateAt"
+	FavoriteService_ExportUserFavorites_FullMethodName   = "/favorite.FavoriteService/ExportUserFavorites"
+	FavoriteService_ImportFavorites_FullMethodName       = "/favorite.FavoriteService/ImportFavorites"
+	FavoriteService_BatchIsFavorite_FullMethodName       = "/favorite.FavoriteService/BatchIsFavorite"
+	FavoriteService_BatchFavoriteCount_FullMethodName    = "/favorite.FavoriteService/BatchFavoriteCount"
+	FavoriteService_WatchFavoriteCount_FullMethodName    = "/favorite.FavoriteService/WatchFavoriteCount"
+	FavoriteService_FriendsFavorited_FullMethodName      = "/favorite.FavoriteService/FriendsFavorited"
+	FavoriteService_CommonFavorites_FullMethodName       = "/favorite.FavoriteService/CommonFavorites"
+	FavoriteService_RelatedContent_FullMethodName        = "/favorite.FavoriteService/RelatedContent"
+	FavoriteService_RegisterContentOwner_FullMethodName  = "/favorite.FavoriteService/RegisterContentOwner"
+	FavoriteService_CreatorReceivedCount_FullMethodName  = "/favorite.FavoriteService/CreatorReceivedCount"
+	FavoriteService_FavoriteCountHistory_FullMethodName  = "/favorite.FavoriteService/FavoriteCountHistory"
 )
 
 // FavoriteServiceClient is the client API for FavoriteService service.
@@ -39,6 +60,27 @@ type FavoriteServiceClient interface {
 	UserFavoritedCount(ctx context.Context, in *UserFavoritedCountRequest, opts ...grpc.CallOption) (*UserFavoritedCountResponse, error)
 	FavoriteCount(ctx context.Context, in *FavoriteCountRequest, opts ...grpc.CallOption) (*FavoriteCountResponse, error)
 	BizFavoriteUser(ctx context.Context, in *BizFavoriteUserRequest, opts ...grpc.CallOption) (*BizFavoriteUserResponse, error)
+	ListFlaggedFavorites(ctx context.Context, in *ListFlaggedFavoritesRequest, opts ...grpc.CallOption) (*ListFlaggedFavoritesResponse, error)
+	PurgeFlaggedFavorites(ctx context.Context, in *PurgeFlaggedFavoritesRequest, opts ...grpc.CallOption) (*PurgeFlaggedFavoritesResponse, error)
+	GetFavoritePrivacy(ctx context.Context, in *GetFavoritePrivacyRequest, opts ...grpc.CallOption) (*GetFavoritePrivacyResponse, error)
+	UpdateFavoritePrivacy(ctx context.Context, in *UpdateFavoritePrivacyRequest, opts ...grpc.CallOption) (*UpdateFavoritePrivacyResponse, error)
+	EraseUserFavorites(ctx context.Context, in *EraseUserFavoritesRequest, opts ...grpc.CallOption) (*EraseUserFavoritesResponse, error)
+	GetEraseStatus(ctx context.Context, in *GetEraseStatusRequest, opts ...grpc.CallOption) (*GetEraseStatusResponse, error)
+	PurgeContent(ctx context.Context, in *PurgeContentRequest, opts ...grpc.CallOption) (*PurgeContentResponse, error)
+	UnFavoriteHistory(ctx context.Context, in *UnFavoriteHistoryRequest, opts ...grpc.CallOption) (*UnFavoriteHistoryResponse, error)
+	QueryActionLogs(ctx context.Context, in *QueryActionLogsRequest, opts ...grpc.CallOption) (*QueryActionLogsResponse, error)
+	FavoriteStateAt(ctx context.Context, in *FavoriteStateAtRequest, opts ...grpc.CallOption) (*FavoriteStateAtResponse, error)
+	ExportUserFavorites(ctx context.Context, in *ExportUserFavoritesRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[ExportUserFavoritesResponse], error)
+	ImportFavorites(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[ImportFavoritesRequest, ImportFavoritesResponse], error)
+	BatchIsFavorite(ctx context.Context, in *BatchIsFavoriteRequest, opts ...grpc.CallOption) (*BatchIsFavoriteResponse, error)
+	BatchFavoriteCount(ctx context.Context, in *BatchFavoriteCountRequest, opts ...grpc.CallOption) (*BatchFavoriteCountResponse, error)
+	WatchFavoriteCount(ctx context.Context, in *WatchFavoriteCountRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[WatchFavoriteCountResponse], error)
+	FriendsFavorited(ctx context.Context, in *FriendsFavoritedRequest, opts ...grpc.CallOption) (*FriendsFavoritedResponse, error)
+	CommonFavorites(ctx context.Context, in *CommonFavoritesRequest, opts ...grpc.CallOption) (*CommonFavoritesResponse, error)
+	RelatedContent(ctx context.Context, in *RelatedContentRequest, opts ...grpc.CallOption) (*RelatedContentResponse, error)
+	RegisterContentOwner(ctx context.Context, in *RegisterContentOwnerRequest, opts ...grpc.CallOption) (*RegisterContentOwnerResponse, error)
+	CreatorReceivedCount(ctx context.Context, in *CreatorReceivedCountRequest, opts ...grpc.CallOption) (*CreatorReceivedCountResponse, error)
+	FavoriteCountHistory(ctx context.Context, in *FavoriteCountHistoryRequest, opts ...grpc.CallOption) (*FavoriteCountHistoryResponse, error)
 }
 
 type favoriteServiceClient struct {
@@ -119,6 +161,237 @@ func (c *favoriteServiceClient) BizFavoriteUser(ctx context.Context, in *BizFavo
 	return out, nil
 }
 
+func (c *favoriteServiceClient) ListFlaggedFavorites(ctx context.Context, in *ListFlaggedFavoritesRequest, opts ...grpc.CallOption) (*ListFlaggedFavoritesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListFlaggedFavoritesResponse)
+	err := c.cc.Invoke(ctx, FavoriteService_ListFlaggedFavorites_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *favoriteServiceClient) PurgeFlaggedFavorites(ctx context.Context, in *PurgeFlaggedFavoritesRequest, opts ...grpc.CallOption) (*PurgeFlaggedFavoritesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(PurgeFlaggedFavoritesResponse)
+	err := c.cc.Invoke(ctx, FavoriteService_PurgeFlaggedFavorites_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *favoriteServiceClient) GetFavoritePrivacy(ctx context.Context, in *GetFavoritePrivacyRequest, opts ...grpc.CallOption) (*GetFavoritePrivacyResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetFavoritePrivacyResponse)
+	err := c.cc.Invoke(ctx, FavoriteService_GetFavoritePrivacy_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *favoriteServiceClient) UpdateFavoritePrivacy(ctx context.Context, in *UpdateFavoritePrivacyRequest, opts ...grpc.CallOption) (*UpdateFavoritePrivacyResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(UpdateFavoritePrivacyResponse)
+	err := c.cc.Invoke(ctx, FavoriteService_UpdateFavoritePrivacy_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *favoriteServiceClient) EraseUserFavorites(ctx context.Context, in *EraseUserFavoritesRequest, opts ...grpc.CallOption) (*EraseUserFavoritesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(EraseUserFavoritesResponse)
+	err := c.cc.Invoke(ctx, FavoriteService_EraseUserFavorites_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *favoriteServiceClient) GetEraseStatus(ctx context.Context, in *GetEraseStatusRequest, opts ...grpc.CallOption) (*GetEraseStatusResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetEraseStatusResponse)
+	err := c.cc.Invoke(ctx, FavoriteService_GetEraseStatus_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *favoriteServiceClient) PurgeContent(ctx context.Context, in *PurgeContentRequest, opts ...grpc.CallOption) (*PurgeContentResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(PurgeContentResponse)
+	err := c.cc.Invoke(ctx, FavoriteService_PurgeContent_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *favoriteServiceClient) UnFavoriteHistory(ctx context.Context, in *UnFavoriteHistoryRequest, opts ...grpc.CallOption) (*UnFavoriteHistoryResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(UnFavoriteHistoryResponse)
+	err := c.cc.Invoke(ctx, FavoriteService_UnFavoriteHistory_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *favoriteServiceClient) QueryActionLogs(ctx context.Context, in *QueryActionLogsRequest, opts ...grpc.CallOption) (*QueryActionLogsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(QueryActionLogsResponse)
+	err := c.cc.Invoke(ctx, FavoriteService_QueryActionLogs_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *favoriteServiceClient) FavoriteStateAt(ctx context.Context, in *FavoriteStateAtRequest, opts ...grpc.CallOption) (*FavoriteStateAtResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(FavoriteStateAtResponse)
+	err := c.cc.Invoke(ctx, FavoriteService_FavoriteStateAt_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *favoriteServiceClient) ExportUserFavorites(ctx context.Context, in *ExportUserFavoritesRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[ExportUserFavoritesResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &FavoriteService_ServiceDesc.Streams[0], FavoriteService_ExportUserFavorites_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[ExportUserFavoritesRequest, ExportUserFavoritesResponse]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type FavoriteService_ExportUserFavoritesClient = grpc.ServerStreamingClient[ExportUserFavoritesResponse]
+
+func (c *favoriteServiceClient) ImportFavorites(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[ImportFavoritesRequest, ImportFavoritesResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &FavoriteService_ServiceDesc.Streams[1], FavoriteService_ImportFavorites_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[ImportFavoritesRequest, ImportFavoritesResponse]{ClientStream: stream}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type FavoriteService_ImportFavoritesClient = grpc.ClientStreamingClient[ImportFavoritesRequest, ImportFavoritesResponse]
+
+func (c *favoriteServiceClient) BatchIsFavorite(ctx context.Context, in *BatchIsFavoriteRequest, opts ...grpc.CallOption) (*BatchIsFavoriteResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(BatchIsFavoriteResponse)
+	err := c.cc.Invoke(ctx, FavoriteService_BatchIsFavorite_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *favoriteServiceClient) BatchFavoriteCount(ctx context.Context, in *BatchFavoriteCountRequest, opts ...grpc.CallOption) (*BatchFavoriteCountResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(BatchFavoriteCountResponse)
+	err := c.cc.Invoke(ctx, FavoriteService_BatchFavoriteCount_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *favoriteServiceClient) WatchFavoriteCount(ctx context.Context, in *WatchFavoriteCountRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[WatchFavoriteCountResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &FavoriteService_ServiceDesc.Streams[2], FavoriteService_WatchFavoriteCount_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[WatchFavoriteCountRequest, WatchFavoriteCountResponse]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type FavoriteService_WatchFavoriteCountClient = grpc.ServerStreamingClient[WatchFavoriteCountResponse]
+
+func (c *favoriteServiceClient) FriendsFavorited(ctx context.Context, in *FriendsFavoritedRequest, opts ...grpc.CallOption) (*FriendsFavoritedResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(FriendsFavoritedResponse)
+	err := c.cc.Invoke(ctx, FavoriteService_FriendsFavorited_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *favoriteServiceClient) CommonFavorites(ctx context.Context, in *CommonFavoritesRequest, opts ...grpc.CallOption) (*CommonFavoritesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CommonFavoritesResponse)
+	err := c.cc.Invoke(ctx, FavoriteService_CommonFavorites_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *favoriteServiceClient) RelatedContent(ctx context.Context, in *RelatedContentRequest, opts ...grpc.CallOption) (*RelatedContentResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RelatedContentResponse)
+	err := c.cc.Invoke(ctx, FavoriteService_RelatedContent_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *favoriteServiceClient) RegisterContentOwner(ctx context.Context, in *RegisterContentOwnerRequest, opts ...grpc.CallOption) (*RegisterContentOwnerResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RegisterContentOwnerResponse)
+	err := c.cc.Invoke(ctx, FavoriteService_RegisterContentOwner_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *favoriteServiceClient) CreatorReceivedCount(ctx context.Context, in *CreatorReceivedCountRequest, opts ...grpc.CallOption) (*CreatorReceivedCountResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CreatorReceivedCountResponse)
+	err := c.cc.Invoke(ctx, FavoriteService_CreatorReceivedCount_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *favoriteServiceClient) FavoriteCountHistory(ctx context.Context, in *FavoriteCountHistoryRequest, opts ...grpc.CallOption) (*FavoriteCountHistoryResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(FavoriteCountHistoryResponse)
+	err := c.cc.Invoke(ctx, FavoriteService_FavoriteCountHistory_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // FavoriteServiceServer is the server API for FavoriteService service.
 // All implementations must embed UnimplementedFavoriteServiceServer
 // for forward compatibility.
@@ -130,6 +403,27 @@ type FavoriteServiceServer interface {
 	UserFavoritedCount(context.Context, *UserFavoritedCountRequest) (*UserFavoritedCountResponse, error)
 	FavoriteCount(context.Context, *FavoriteCountRequest) (*FavoriteCountResponse, error)
 	BizFavoriteUser(context.Context, *BizFavoriteUserRequest) (*BizFavoriteUserResponse, error)
+	ListFlaggedFavorites(context.Context, *ListFlaggedFavoritesRequest) (*ListFlaggedFavoritesResponse, error)
+	PurgeFlaggedFavorites(context.Context, *PurgeFlaggedFavoritesRequest) (*PurgeFlaggedFavoritesResponse, error)
+	GetFavoritePrivacy(context.Context, *GetFavoritePrivacyRequest) (*GetFavoritePrivacyResponse, error)
+	UpdateFavoritePrivacy(context.Context, *UpdateFavoritePrivacyRequest) (*UpdateFavoritePrivacyResponse, error)
+	EraseUserFavorites(context.Context, *EraseUserFavoritesRequest) (*EraseUserFavoritesResponse, error)
+	GetEraseStatus(context.Context, *GetEraseStatusRequest) (*GetEraseStatusResponse, error)
+	PurgeContent(context.Context, *PurgeContentRequest) (*PurgeContentResponse, error)
+	UnFavoriteHistory(context.Context, *UnFavoriteHistoryRequest) (*UnFavoriteHistoryResponse, error)
+	QueryActionLogs(context.Context, *QueryActionLogsRequest) (*QueryActionLogsResponse, error)
+	FavoriteStateAt(context.Context, *FavoriteStateAtRequest) (*FavoriteStateAtResponse, error)
+	ExportUserFavorites(*ExportUserFavoritesRequest, grpc.ServerStreamingServer[ExportUserFavoritesResponse]) error
+	ImportFavorites(grpc.ClientStreamingServer[ImportFavoritesRequest, ImportFavoritesResponse]) error
+	BatchIsFavorite(context.Context, *BatchIsFavoriteRequest) (*BatchIsFavoriteResponse, error)
+	BatchFavoriteCount(context.Context, *BatchFavoriteCountRequest) (*BatchFavoriteCountResponse, error)
+	WatchFavoriteCount(*WatchFavoriteCountRequest, grpc.ServerStreamingServer[WatchFavoriteCountResponse]) error
+	FriendsFavorited(context.Context, *FriendsFavoritedRequest) (*FriendsFavoritedResponse, error)
+	CommonFavorites(context.Context, *CommonFavoritesRequest) (*CommonFavoritesResponse, error)
+	RelatedContent(context.Context, *RelatedContentRequest) (*RelatedContentResponse, error)
+	RegisterContentOwner(context.Context, *RegisterContentOwnerRequest) (*RegisterContentOwnerResponse, error)
+	CreatorReceivedCount(context.Context, *CreatorReceivedCountRequest) (*CreatorReceivedCountResponse, error)
+	FavoriteCountHistory(context.Context, *FavoriteCountHistoryRequest) (*FavoriteCountHistoryResponse, error)
 	mustEmbedUnimplementedFavoriteServiceServer()
 }
 
@@ -161,6 +455,69 @@ func (UnimplementedFavoriteServiceServer) FavoriteCount(context.Context, *Favori
 func (UnimplementedFavoriteServiceServer) BizFavoriteUser(context.Context, *BizFavoriteUserRequest) (*BizFavoriteUserResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method BizFavoriteUser not implemented")
 }
+func (UnimplementedFavoriteServiceServer) ListFlaggedFavorites(context.Context, *ListFlaggedFavoritesRequest) (*ListFlaggedFavoritesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListFlaggedFavorites not implemented")
+}
+func (UnimplementedFavoriteServiceServer) PurgeFlaggedFavorites(context.Context, *PurgeFlaggedFavoritesRequest) (*PurgeFlaggedFavoritesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PurgeFlaggedFavorites not implemented")
+}
+func (UnimplementedFavoriteServiceServer) GetFavoritePrivacy(context.Context, *GetFavoritePrivacyRequest) (*GetFavoritePrivacyResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetFavoritePrivacy not implemented")
+}
+func (UnimplementedFavoriteServiceServer) UpdateFavoritePrivacy(context.Context, *UpdateFavoritePrivacyRequest) (*UpdateFavoritePrivacyResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateFavoritePrivacy not implemented")
+}
+func (UnimplementedFavoriteServiceServer) EraseUserFavorites(context.Context, *EraseUserFavoritesRequest) (*EraseUserFavoritesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method EraseUserFavorites not implemented")
+}
+func (UnimplementedFavoriteServiceServer) GetEraseStatus(context.Context, *GetEraseStatusRequest) (*GetEraseStatusResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetEraseStatus not implemented")
+}
+func (UnimplementedFavoriteServiceServer) PurgeContent(context.Context, *PurgeContentRequest) (*PurgeContentResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PurgeContent not implemented")
+}
+func (UnimplementedFavoriteServiceServer) UnFavoriteHistory(context.Context, *UnFavoriteHistoryRequest) (*UnFavoriteHistoryResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UnFavoriteHistory not implemented")
+}
+func (UnimplementedFavoriteServiceServer) QueryActionLogs(context.Context, *QueryActionLogsRequest) (*QueryActionLogsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method QueryActionLogs not implemented")
+}
+func (UnimplementedFavoriteServiceServer) FavoriteStateAt(context.Context, *FavoriteStateAtRequest) (*FavoriteStateAtResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method FavoriteStateAt not implemented")
+}
+func (UnimplementedFavoriteServiceServer) ExportUserFavorites(*ExportUserFavoritesRequest, grpc.ServerStreamingServer[ExportUserFavoritesResponse]) error {
+	return status.Errorf(codes.Unimplemented, "method ExportUserFavorites not implemented")
+}
+func (UnimplementedFavoriteServiceServer) ImportFavorites(grpc.ClientStreamingServer[ImportFavoritesRequest, ImportFavoritesResponse]) error {
+	return status.Errorf(codes.Unimplemented, "method ImportFavorites not implemented")
+}
+func (UnimplementedFavoriteServiceServer) BatchIsFavorite(context.Context, *BatchIsFavoriteRequest) (*BatchIsFavoriteResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BatchIsFavorite not implemented")
+}
+func (UnimplementedFavoriteServiceServer) BatchFavoriteCount(context.Context, *BatchFavoriteCountRequest) (*BatchFavoriteCountResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BatchFavoriteCount not implemented")
+}
+func (UnimplementedFavoriteServiceServer) WatchFavoriteCount(*WatchFavoriteCountRequest, grpc.ServerStreamingServer[WatchFavoriteCountResponse]) error {
+	return status.Errorf(codes.Unimplemented, "method WatchFavoriteCount not implemented")
+}
+func (UnimplementedFavoriteServiceServer) FriendsFavorited(context.Context, *FriendsFavoritedRequest) (*FriendsFavoritedResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method FriendsFavorited not implemented")
+}
+func (UnimplementedFavoriteServiceServer) CommonFavorites(context.Context, *CommonFavoritesRequest) (*CommonFavoritesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CommonFavorites not implemented")
+}
+func (UnimplementedFavoriteServiceServer) RelatedContent(context.Context, *RelatedContentRequest) (*RelatedContentResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RelatedContent not implemented")
+}
+func (UnimplementedFavoriteServiceServer) RegisterContentOwner(context.Context, *RegisterContentOwnerRequest) (*RegisterContentOwnerResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RegisterContentOwner not implemented")
+}
+func (UnimplementedFavoriteServiceServer) CreatorReceivedCount(context.Context, *CreatorReceivedCountRequest) (*CreatorReceivedCountResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreatorReceivedCount not implemented")
+}
+func (UnimplementedFavoriteServiceServer) FavoriteCountHistory(context.Context, *FavoriteCountHistoryRequest) (*FavoriteCountHistoryResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method FavoriteCountHistory not implemented")
+}
 func (UnimplementedFavoriteServiceServer) mustEmbedUnimplementedFavoriteServiceServer() {}
 func (UnimplementedFavoriteServiceServer) testEmbeddedByValue()                         {}
 
@@ -308,40 +665,697 @@ func _FavoriteService_BizFavoriteUser_Handler(srv interface{}, ctx context.Conte
 	return interceptor(ctx, in, info, handler)
 }
 
-// FavoriteService_ServiceDesc is the grpc.ServiceDesc for FavoriteService service.
-// It's only intended for direct use with grpc.RegisterService,
-// and not to be introspected or modified (even as a copy)
-var FavoriteService_ServiceDesc = grpc.ServiceDesc{
-	ServiceName: "favorite.FavoriteService",
-	HandlerType: (*FavoriteServiceServer)(nil),
-	Methods: []grpc.MethodDesc{
-		{
-			MethodName: "FavoriteAction",
-			Handler:    _FavoriteService_FavoriteAction_Handler,
-		},
-		{
-			MethodName: "FavoriteList",
-			Handler:    _FavoriteService_FavoriteList_Handler,
-		},
-		{
-			MethodName: "IsFavorite",
-			Handler:    _FavoriteService_IsFavorite_Handler,
-		},
-		{
-			MethodName: "UserFavoriteCount",
-			Handler:    _FavoriteService_UserFavoriteCount_Handler,
-		},
-		{
-			MethodName: "UserFavoritedCount",
-			Handler:    _FavoriteService_UserFavoritedCount_Handler,
-		},
-		{
-			MethodName: "FavoriteCount",
-			Handler:    _FavoriteService_FavoriteCount_Handler,
-		},
-		{
-			MethodName: "BizFavoriteUser",
-			Handler:    _FavoriteService_BizFavoriteUser_Handler,
+func _FavoriteService_ListFlaggedFavorites_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListFlaggedFavoritesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FavoriteServiceServer).ListFlaggedFavorites(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FavoriteService_ListFlaggedFavorites_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FavoriteServiceServer).ListFlaggedFavorites(ctx, req.(*ListFlaggedFavoritesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FavoriteService_PurgeFlaggedFavorites_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PurgeFlaggedFavoritesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FavoriteServiceServer).PurgeFlaggedFavorites(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FavoriteService_PurgeFlaggedFavorites_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FavoriteServiceServer).PurgeFlaggedFavorites(ctx, req.(*PurgeFlaggedFavoritesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FavoriteService_GetFavoritePrivacy_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetFavoritePrivacyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FavoriteServiceServer).GetFavoritePrivacy(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FavoriteService_GetFavoritePrivacy_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FavoriteServiceServer).GetFavoritePrivacy(ctx, req.(*GetFavoritePrivacyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FavoriteService_UpdateFavoritePrivacy_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateFavoritePrivacyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FavoriteServiceServer).UpdateFavoritePrivacy(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FavoriteService_UpdateFavoritePrivacy_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FavoriteServiceServer).UpdateFavoritePrivacy(ctx, req.(*UpdateFavoritePrivacyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FavoriteService_EraseUserFavorites_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EraseUserFavoritesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FavoriteServiceServer).EraseUserFavorites(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FavoriteService_EraseUserFavorites_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FavoriteServiceServer).EraseUserFavorites(ctx, req.(*EraseUserFavoritesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FavoriteService_GetEraseStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetEraseStatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FavoriteServiceServer).GetEraseStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FavoriteService_GetEraseStatus_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FavoriteServiceServer).GetEraseStatus(ctx, req.(*GetEraseStatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FavoriteService_PurgeContent_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PurgeContentRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FavoriteServiceServer).PurgeContent(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FavoriteService_PurgeContent_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FavoriteServiceServer).PurgeContent(ctx, req.(*PurgeContentRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FavoriteService_UnFavoriteHistory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UnFavoriteHistoryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FavoriteServiceServer).UnFavoriteHistory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FavoriteService_UnFavoriteHistory_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FavoriteServiceServer).UnFavoriteHistory(ctx, req.(*UnFavoriteHistoryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FavoriteService_QueryActionLogs_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(QueryActionLogsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FavoriteServiceServer).QueryActionLogs(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FavoriteService_QueryActionLogs_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FavoriteServiceServer).QueryActionLogs(ctx, req.(*QueryActionLogsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FavoriteService_FavoriteStateAt_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(FavoriteStateAtRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FavoriteServiceServer).FavoriteStateAt(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FavoriteService_FavoriteStateAt_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FavoriteServiceServer).FavoriteStateAt(ctx, req.(*FavoriteStateAtRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FavoriteService_ExportUserFavorites_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ExportUserFavoritesRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(FavoriteServiceServer).ExportUserFavorites(m, &grpc.GenericServerStream[ExportUserFavoritesRequest, ExportUserFavoritesResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type FavoriteService_ExportUserFavoritesServer = grpc.ServerStreamingServer[ExportUserFavoritesResponse]
+
+func _FavoriteService_ImportFavorites_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(FavoriteServiceServer).ImportFavorites(&grpc.GenericServerStream[ImportFavoritesRequest, ImportFavoritesResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type FavoriteService_ImportFavoritesServer = grpc.ClientStreamingServer[ImportFavoritesRequest, ImportFavoritesResponse]
+
+func _FavoriteService_BatchIsFavorite_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BatchIsFavoriteRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FavoriteServiceServer).BatchIsFavorite(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FavoriteService_BatchIsFavorite_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FavoriteServiceServer).BatchIsFavorite(ctx, req.(*BatchIsFavoriteRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FavoriteService_BatchFavoriteCount_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BatchFavoriteCountRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FavoriteServiceServer).BatchFavoriteCount(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FavoriteService_BatchFavoriteCount_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FavoriteServiceServer).BatchFavoriteCount(ctx, req.(*BatchFavoriteCountRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FavoriteService_WatchFavoriteCount_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchFavoriteCountRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(FavoriteServiceServer).WatchFavoriteCount(m, &grpc.GenericServerStream[WatchFavoriteCountRequest, WatchFavoriteCountResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type FavoriteService_WatchFavoriteCountServer = grpc.ServerStreamingServer[WatchFavoriteCountResponse]
+
+func _FavoriteService_FriendsFavorited_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(FriendsFavoritedRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FavoriteServiceServer).FriendsFavorited(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FavoriteService_FriendsFavorited_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FavoriteServiceServer).FriendsFavorited(ctx, req.(*FriendsFavoritedRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FavoriteService_CommonFavorites_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CommonFavoritesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FavoriteServiceServer).CommonFavorites(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FavoriteService_CommonFavorites_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FavoriteServiceServer).CommonFavorites(ctx, req.(*CommonFavoritesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FavoriteService_RelatedContent_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RelatedContentRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FavoriteServiceServer).RelatedContent(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FavoriteService_RelatedContent_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FavoriteServiceServer).RelatedContent(ctx, req.(*RelatedContentRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FavoriteService_RegisterContentOwner_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RegisterContentOwnerRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FavoriteServiceServer).RegisterContentOwner(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FavoriteService_RegisterContentOwner_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FavoriteServiceServer).RegisterContentOwner(ctx, req.(*RegisterContentOwnerRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FavoriteService_CreatorReceivedCount_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreatorReceivedCountRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FavoriteServiceServer).CreatorReceivedCount(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FavoriteService_CreatorReceivedCount_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FavoriteServiceServer).CreatorReceivedCount(ctx, req.(*CreatorReceivedCountRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FavoriteService_FavoriteCountHistory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(FavoriteCountHistoryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FavoriteServiceServer).FavoriteCountHistory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FavoriteService_FavoriteCountHistory_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FavoriteServiceServer).FavoriteCountHistory(ctx, req.(*FavoriteCountHistoryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// FavoriteService_ServiceDesc is the grpc.ServiceDesc for FavoriteService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var FavoriteService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "favorite.FavoriteService",
+	HandlerType: (*FavoriteServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "FavoriteAction",
+			Handler:    _FavoriteService_FavoriteAction_Handler,
+		},
+		{
+			MethodName: "FavoriteList",
+			Handler:    _FavoriteService_FavoriteList_Handler,
+		},
+		{
+			MethodName: "IsFavorite",
+			Handler:    _FavoriteService_IsFavorite_Handler,
+		},
+		{
+			MethodName: "UserFavoriteCount",
+			Handler:    _FavoriteService_UserFavoriteCount_Handler,
+		},
+		{
+			MethodName: "UserFavoritedCount",
+			Handler:    _FavoriteService_UserFavoritedCount_Handler,
+		},
+		{
+			MethodName: "FavoriteCount",
+			Handler:    _FavoriteService_FavoriteCount_Handler,
+		},
+		{
+			MethodName: "BizFavoriteUser",
+			Handler:    _FavoriteService_BizFavoriteUser_Handler,
+		},
+		{
+			MethodName: "ListFlaggedFavorites",
+			Handler:    _FavoriteService_ListFlaggedFavorites_Handler,
+		},
+		{
+			MethodName: "PurgeFlaggedFavorites",
+			Handler:    _FavoriteService_PurgeFlaggedFavorites_Handler,
+		},
+		{
+			MethodName: "GetFavoritePrivacy",
+			Handler:    _FavoriteService_GetFavoritePrivacy_Handler,
+		},
+		{
+			MethodName: "UpdateFavoritePrivacy",
+			Handler:    _FavoriteService_UpdateFavoritePrivacy_Handler,
+		},
+		{
+			MethodName: "EraseUserFavorites",
+			Handler:    _FavoriteService_EraseUserFavorites_Handler,
+		},
+		{
+			MethodName: "GetEraseStatus",
+			Handler:    _FavoriteService_GetEraseStatus_Handler,
+		},
+		{
+			MethodName: "PurgeContent",
+			Handler:    _FavoriteService_PurgeContent_Handler,
+		},
+		{
+			MethodName: "UnFavoriteHistory",
+			Handler:    _FavoriteService_UnFavoriteHistory_Handler,
+		},
+		{
+			MethodName: "QueryActionLogs",
+			Handler:    _FavoriteService_QueryActionLogs_Handler,
+		},
+		{
+			MethodName: "FavoriteStateAt",
+			Handler:    _FavoriteService_FavoriteStateAt_Handler,
+		},
+		{
+			MethodName: "BatchIsFavorite",
+			Handler:    _FavoriteService_BatchIsFavorite_Handler,
+		},
+		{
+			MethodName: "BatchFavoriteCount",
+			Handler:    _FavoriteService_BatchFavoriteCount_Handler,
+		},
+		{
+			MethodName: "FriendsFavorited",
+			Handler:    _FavoriteService_FriendsFavorited_Handler,
+		},
+		{
+			MethodName: "CommonFavorites",
+			Handler:    _FavoriteService_CommonFavorites_Handler,
+		},
+		{
+			MethodName: "RelatedContent",
+			Handler:    _FavoriteService_RelatedContent_Handler,
+		},
+		{
+			MethodName: "RegisterContentOwner",
+			Handler:    _FavoriteService_RegisterContentOwner_Handler,
+		},
+		{
+			MethodName: "CreatorReceivedCount",
+			Handler:    _FavoriteService_CreatorReceivedCount_Handler,
+		},
+		{
+			MethodName: "FavoriteCountHistory",
+			Handler:    _FavoriteService_FavoriteCountHistory_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "ExportUserFavorites",
+			Handler:       _FavoriteService_ExportUserFavorites_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "ImportFavorites",
+			Handler:       _FavoriteService_ImportFavorites_Handler,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "WatchFavoriteCount",
+			Handler:       _FavoriteService_WatchFavoriteCount_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "api/favorite.proto",
+}
+
+const (
+	JobAdminService_ListJobs_FullMethodName   = "/favorite.JobAdminService/ListJobs"
+	JobAdminService_PauseJob_FullMethodName   = "/favorite.JobAdminService/PauseJob"
+	JobAdminService_ResumeJob_FullMethodName  = "/favorite.JobAdminService/ResumeJob"
+	JobAdminService_TriggerJob_FullMethodName = "/favorite.JobAdminService/TriggerJob"
+)
+
+// JobAdminServiceClient is the client API for JobAdminService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type JobAdminServiceClient interface {
+	ListJobs(ctx context.Context, in *ListJobsRequest, opts ...grpc.CallOption) (*ListJobsResponse, error)
+	PauseJob(ctx context.Context, in *PauseJobRequest, opts ...grpc.CallOption) (*PauseJobResponse, error)
+	ResumeJob(ctx context.Context, in *ResumeJobRequest, opts ...grpc.CallOption) (*ResumeJobResponse, error)
+	TriggerJob(ctx context.Context, in *TriggerJobRequest, opts ...grpc.CallOption) (*TriggerJobResponse, error)
+}
+
+type jobAdminServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewJobAdminServiceClient(cc grpc.ClientConnInterface) JobAdminServiceClient {
+	return &jobAdminServiceClient{cc}
+}
+
+func (c *jobAdminServiceClient) ListJobs(ctx context.Context, in *ListJobsRequest, opts ...grpc.CallOption) (*ListJobsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListJobsResponse)
+	err := c.cc.Invoke(ctx, JobAdminService_ListJobs_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *jobAdminServiceClient) PauseJob(ctx context.Context, in *PauseJobRequest, opts ...grpc.CallOption) (*PauseJobResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(PauseJobResponse)
+	err := c.cc.Invoke(ctx, JobAdminService_PauseJob_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *jobAdminServiceClient) ResumeJob(ctx context.Context, in *ResumeJobRequest, opts ...grpc.CallOption) (*ResumeJobResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ResumeJobResponse)
+	err := c.cc.Invoke(ctx, JobAdminService_ResumeJob_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *jobAdminServiceClient) TriggerJob(ctx context.Context, in *TriggerJobRequest, opts ...grpc.CallOption) (*TriggerJobResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(TriggerJobResponse)
+	err := c.cc.Invoke(ctx, JobAdminService_TriggerJob_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// JobAdminServiceServer is the server API for JobAdminService service.
+// All implementations must embed UnimplementedJobAdminServiceServer
+// for forward compatibility.
+type JobAdminServiceServer interface {
+	ListJobs(context.Context, *ListJobsRequest) (*ListJobsResponse, error)
+	PauseJob(context.Context, *PauseJobRequest) (*PauseJobResponse, error)
+	ResumeJob(context.Context, *ResumeJobRequest) (*ResumeJobResponse, error)
+	TriggerJob(context.Context, *TriggerJobRequest) (*TriggerJobResponse, error)
+	mustEmbedUnimplementedJobAdminServiceServer()
+}
+
+// UnimplementedJobAdminServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedJobAdminServiceServer struct{}
+
+func (UnimplementedJobAdminServiceServer) ListJobs(context.Context, *ListJobsRequest) (*ListJobsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListJobs not implemented")
+}
+func (UnimplementedJobAdminServiceServer) PauseJob(context.Context, *PauseJobRequest) (*PauseJobResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PauseJob not implemented")
+}
+func (UnimplementedJobAdminServiceServer) ResumeJob(context.Context, *ResumeJobRequest) (*ResumeJobResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ResumeJob not implemented")
+}
+func (UnimplementedJobAdminServiceServer) TriggerJob(context.Context, *TriggerJobRequest) (*TriggerJobResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method TriggerJob not implemented")
+}
+func (UnimplementedJobAdminServiceServer) mustEmbedUnimplementedJobAdminServiceServer() {}
+func (UnimplementedJobAdminServiceServer) testEmbeddedByValue()                         {}
+
+// UnsafeJobAdminServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to JobAdminServiceServer will
+// result in compilation errors.
+type UnsafeJobAdminServiceServer interface {
+	mustEmbedUnimplementedJobAdminServiceServer()
+}
+
+func RegisterJobAdminServiceServer(s grpc.ServiceRegistrar, srv JobAdminServiceServer) {
+	// If the following call pancis, it indicates UnimplementedJobAdminServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&JobAdminService_ServiceDesc, srv)
+}
+
+func _JobAdminService_ListJobs_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListJobsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(JobAdminServiceServer).ListJobs(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: JobAdminService_ListJobs_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(JobAdminServiceServer).ListJobs(ctx, req.(*ListJobsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _JobAdminService_PauseJob_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PauseJobRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(JobAdminServiceServer).PauseJob(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: JobAdminService_PauseJob_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(JobAdminServiceServer).PauseJob(ctx, req.(*PauseJobRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _JobAdminService_ResumeJob_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ResumeJobRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(JobAdminServiceServer).ResumeJob(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: JobAdminService_ResumeJob_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(JobAdminServiceServer).ResumeJob(ctx, req.(*ResumeJobRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _JobAdminService_TriggerJob_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TriggerJobRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(JobAdminServiceServer).TriggerJob(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: JobAdminService_TriggerJob_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(JobAdminServiceServer).TriggerJob(ctx, req.(*TriggerJobRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// JobAdminService_ServiceDesc is the grpc.ServiceDesc for JobAdminService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var JobAdminService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "favorite.JobAdminService",
+	HandlerType: (*JobAdminServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ListJobs",
+			Handler:    _JobAdminService_ListJobs_Handler,
+		},
+		{
+			MethodName: "PauseJob",
+			Handler:    _JobAdminService_PauseJob_Handler,
+		},
+		{
+			MethodName: "ResumeJob",
+			Handler:    _JobAdminService_ResumeJob_Handler,
+		},
+		{
+			MethodName: "TriggerJob",
+			Handler:    _JobAdminService_TriggerJob_Handler,
 		},
 	},
 	Streams:  []grpc.StreamDesc{},
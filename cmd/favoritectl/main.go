@@ -0,0 +1,183 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"slices"
+	"time"
+
+	"github.com/crazyfrankie/favorite/internal/biz/repository"
+	"github.com/crazyfrankie/favorite/internal/ioc"
+)
+
+const usage = `favoritectl 点赞数据排查与修复工具
+
+Usage:
+  favoritectl user   -uid <uid>              查看用户在 Redis 与 MySQL 中的点赞
+  favoritectl item   -biz <biz> -id <bizId>  查看内容在 Redis 与 MySQL 中的点赞数及点赞用户
+  favoritectl diff   -biz <biz> -id <bizId>  对比内容在 Redis 与 MySQL 中的差异
+  favoritectl diff   -uid <uid>              对比用户在 Redis 与 MySQL 中的差异
+  favoritectl resync -biz <biz> -id <bizId>  以 MySQL 为准重建内容的点赞数据
+  favoritectl sync                           立即将 Redis 点赞数同步到 MySQL
+  favoritectl biz                            列出所有业务类型
+`
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Print(usage)
+		os.Exit(2)
+	}
+
+	fs := flag.NewFlagSet(os.Args[1], flag.ExitOnError)
+	uid := fs.Int64("uid", 0, "user id")
+	biz := fs.String("biz", "", "biz type")
+	bizId := fs.Int64("id", 0, "biz id")
+	timeout := fs.Duration("timeout", time.Minute, "command timeout")
+	_ = fs.Parse(os.Args[2:])
+
+	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+	defer cancel()
+
+	repo := ioc.InitRepo()
+
+	var err error
+	switch os.Args[1] {
+	case "user":
+		err = inspectUser(ctx, repo, *uid)
+	case "item":
+		err = inspectItem(ctx, repo, *biz, *bizId)
+	case "diff":
+		if *uid > 0 {
+			err = diffUser(ctx, repo, *uid)
+		} else {
+			err = diffItem(ctx, repo, *biz, *bizId)
+		}
+	case "resync":
+		err = resync(ctx, repo, *biz, *bizId)
+	case "sync":
+		// 手动同步不设阈值, 全量覆盖数据库
+		err = repo.SyncFavoritesCount(ctx, 0)
+		if err == nil {
+			fmt.Println("sync finished")
+		}
+	case "biz":
+		err = listBiz(ctx, repo)
+	default:
+		fmt.Print(usage)
+		os.Exit(2)
+	}
+
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s failed: %v\n", os.Args[1], err)
+		os.Exit(1)
+	}
+}
+
+func inspectUser(ctx context.Context, repo *repository.FavoriteRepo, uid int64) error {
+	if uid <= 0 {
+		return fmt.Errorf("-uid is required")
+	}
+	snap, err := repo.InspectUser(ctx, uid)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("redis (%d): %v\n", len(snap.CacheItems), snap.CacheItems)
+	fmt.Printf("mysql (%d): %v\n", len(snap.DBItems), snap.DBItems)
+	return nil
+}
+
+func inspectItem(ctx context.Context, repo *repository.FavoriteRepo, biz string, bizId int64) error {
+	if biz == "" || bizId <= 0 {
+		return fmt.Errorf("-biz and -id are required")
+	}
+	snap, err := repo.InspectContent(ctx, biz, bizId)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("redis count: %d, likers (%d): %v\n", snap.CacheCount, len(snap.CacheUsers), snap.CacheUsers)
+	fmt.Printf("mysql count: %d, likers (%d): %v\n", snap.DBCount, len(snap.DBUsers), snap.DBUsers)
+	return nil
+}
+
+func diffUser(ctx context.Context, repo *repository.FavoriteRepo, uid int64) error {
+	snap, err := repo.InspectUser(ctx, uid)
+	if err != nil {
+		return err
+	}
+
+	onlyCache, onlyDB := diff(snap.CacheItems, snap.DBItems)
+	fmt.Printf("only in redis: %v\n", onlyCache)
+	fmt.Printf("only in mysql: %v\n", onlyDB)
+	return nil
+}
+
+func diffItem(ctx context.Context, repo *repository.FavoriteRepo, biz string, bizId int64) error {
+	if biz == "" || bizId <= 0 {
+		return fmt.Errorf("-biz and -id are required")
+	}
+	snap, err := repo.InspectContent(ctx, biz, bizId)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("count: redis %d, mysql %d, likers in mysql %d\n", snap.CacheCount, snap.DBCount, len(snap.DBUsers))
+	onlyCache, onlyDB := diff(snap.CacheUsers, snap.DBUsers)
+	fmt.Printf("likers only in redis: %v\n", onlyCache)
+	fmt.Printf("likers only in mysql: %v\n", onlyDB)
+	return nil
+}
+
+func resync(ctx context.Context, repo *repository.FavoriteRepo, biz string, bizId int64) error {
+	if biz == "" || bizId <= 0 {
+		return fmt.Errorf("-biz and -id are required")
+	}
+	if err := repo.ResyncContent(ctx, biz, bizId); err != nil {
+		return err
+	}
+
+	fmt.Printf("%s:%d resynced\n", biz, bizId)
+	return nil
+}
+
+func listBiz(ctx context.Context, repo *repository.FavoriteRepo) error {
+	types, err := repo.BizTypes(ctx)
+	if err != nil {
+		return err
+	}
+
+	slices.Sort(types)
+	for _, t := range types {
+		fmt.Println(t)
+	}
+	return nil
+}
+
+// diff 返回只在 a 中和只在 b 中的元素
+func diff[T comparable](a, b []T) ([]T, []T) {
+	inA := make(map[T]struct{}, len(a))
+	for _, v := range a {
+		inA[v] = struct{}{}
+	}
+	inB := make(map[T]struct{}, len(b))
+	for _, v := range b {
+		inB[v] = struct{}{}
+	}
+
+	var onlyA, onlyB []T
+	for _, v := range a {
+		if _, ok := inB[v]; !ok {
+			onlyA = append(onlyA, v)
+		}
+	}
+	for _, v := range b {
+		if _, ok := inA[v]; !ok {
+			onlyB = append(onlyB, v)
+		}
+	}
+
+	return onlyA, onlyB
+}
@@ -0,0 +1,119 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strconv"
+
+	"github.com/crazyfrankie/favorite/internal/biz/domain"
+	"github.com/crazyfrankie/favorite/internal/biz/service"
+	"github.com/crazyfrankie/favorite/internal/ioc"
+)
+
+// 离线导入历史点赞, 支持 JSON Lines 与 CSV(user_id,biz,biz_id,liked_at, 带表头) 两种格式
+//
+//	favoriteimport -file likes.jsonl -format jsonl -rebuild
+func main() {
+	file := flag.String("file", "", "path of the file to import")
+	format := flag.String("format", "jsonl", "file format: jsonl or csv")
+	batch := flag.Int("batch", 500, "records per batch")
+	rebuild := flag.Bool("rebuild", false, "rebuild redis structures for imported favorites")
+	flag.Parse()
+
+	if *file == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+	f, err := os.Open(*file)
+	if err != nil {
+		log.Fatalf("failed to open file: %v", err)
+	}
+	defer f.Close()
+
+	ctx := context.Background()
+	importer := service.NewImporter(ioc.InitRepo(), *batch, *rebuild)
+
+	switch *format {
+	case "jsonl":
+		err = readJSONL(f, func(r domain.ImportRecord) { importer.Add(ctx, r) })
+	case "csv":
+		err = readCSV(f, func(r domain.ImportRecord) { importer.Add(ctx, r) })
+	default:
+		log.Fatalf("unknown format: %s", *format)
+	}
+	if err != nil {
+		log.Printf("stop reading input: %v", err)
+	}
+
+	res := importer.Close(ctx)
+	fmt.Printf("received: %d, imported: %d, duplicates: %d, invalid: %d\n",
+		res.Received, res.Imported, res.Duplicates, res.Invalid)
+	for _, e := range res.Errors {
+		fmt.Printf("batch %d failed: %s\n", e.Batch, e.Err)
+	}
+	if len(res.Errors) > 0 {
+		os.Exit(1)
+	}
+}
+
+type record struct {
+	UserId  int64  `json:"user_id"`
+	Biz     string `json:"biz"`
+	BizId   int64  `json:"biz_id"`
+	LikedAt int64  `json:"liked_at"`
+}
+
+func readJSONL(r io.Reader, fn func(domain.ImportRecord)) error {
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 64*1024), 1024*1024)
+	line := 0
+	for sc.Scan() {
+		line++
+		if len(sc.Bytes()) == 0 {
+			continue
+		}
+
+		var rec record
+		if err := json.Unmarshal(sc.Bytes(), &rec); err != nil {
+			log.Printf("skip line %d: %v", line, err)
+			// 交给 Importer 统计为无效记录
+			fn(domain.ImportRecord{})
+			continue
+		}
+		fn(domain.ImportRecord{UserId: rec.UserId, Biz: rec.Biz, BizId: rec.BizId, LikedAt: rec.LikedAt})
+	}
+
+	return sc.Err()
+}
+
+func readCSV(r io.Reader, fn func(domain.ImportRecord)) error {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = 4
+
+	// 跳过表头
+	if _, err := cr.Read(); err != nil {
+		return err
+	}
+	for {
+		row, err := cr.Read()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		uid, _ := strconv.ParseInt(row[0], 10, 64)
+		bizId, _ := strconv.ParseInt(row[2], 10, 64)
+		likedAt, _ := strconv.ParseInt(row[3], 10, 64)
+		fn(domain.ImportRecord{UserId: uid, Biz: row[1], BizId: bizId, LikedAt: likedAt})
+	}
+}
@@ -10,24 +10,36 @@ import (
 	"github.com/oklog/run"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"github.com/robfig/cron/v3"
-	clientv3 "go.etcd.io/etcd/client/v3"
 	"go.uber.org/zap"
 
+	"github.com/crazyfrankie/favorite/api/rpc_gen/favorite"
 	"github.com/crazyfrankie/favorite/internal/biz/service"
 	"github.com/crazyfrankie/favorite/internal/config"
 	"github.com/crazyfrankie/favorite/internal/ioc"
+	"github.com/crazyfrankie/favorite/job"
 	"github.com/crazyfrankie/favorite/job/scheduler"
 	"github.com/crazyfrankie/favorite/rpc"
 )
 
 func main() {
-	svc := ioc.InitServer()
-	server := rpc.NewServer(initRegistry(), svc)
-	cr := initCronJob(zap.NewExample(), svc)
+	// 业务代码通过 zap.L() 记录日志, 需替换全局 logger, 否则日志被丢弃
+	logger, err := zap.NewProduction()
+	if err != nil {
+		log.Fatalf("failed to init logger: %v", err)
+	}
+	defer logger.Sync()
+	zap.ReplaceGlobals(logger)
+
+	app := ioc.InitApp()
+	server := app.Server
+	cr, jobs := initCronJob(logger, app.Favorite, app.Locker, app.Pauses)
 
 	// 启动定时任务
 	cr.Start()
 
+	// 任务管理接口与业务接口共用 gRPC 端口, 需在 Serve 前注册, 仅管理员可调用
+	favorite.RegisterJobAdminServiceServer(server.Server, scheduler.NewAdminServer(jobs))
+
 	g := &run.Group{}
 
 	g.Add(func() error {
@@ -36,15 +48,62 @@ func main() {
 		server.Shutdown()
 	})
 
-	favoriteServer := &http.Server{Addr: ":9092"}
+	consumerCtx, consumerCancel := context.WithCancel(context.Background())
+	g.Add(func() error {
+		return app.Content.Start(consumerCtx)
+	}, func(err error) {
+		consumerCancel()
+	})
+
+	pausesCtx, pausesCancel := context.WithCancel(context.Background())
+	g.Add(func() error {
+		return jobs.WatchPaused(pausesCtx)
+	}, func(err error) {
+		pausesCancel()
+	})
+
+	countsCtx, countsCancel := context.WithCancel(context.Background())
+	g.Add(func() error {
+		return app.Counts.Start(countsCtx)
+	}, func(err error) {
+		countsCancel()
+	})
+
+	gateway, err := rpc.NewGateway(server.Port)
+	if err != nil {
+		panic(err)
+	}
+	defer gateway.Close()
+
+	// 网关对外提供服务, 单独监听, 不暴露下面的 /metrics 与 /admin/jobs
+	gatewayAddr := config.GetConf().Server.GatewayPort
+	if gatewayAddr == "" {
+		gatewayAddr = ":9093"
+	}
+	gatewayServer := &http.Server{Addr: gatewayAddr, Handler: gateway}
+	g.Add(func() error {
+		return gatewayServer.ListenAndServe()
+	}, func(err error) {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		if err := gatewayServer.Shutdown(ctx); err != nil {
+			log.Printf("failed to shutdown gateway server: %v", err)
+		}
+	})
+
+	mux := http.NewServeMux()
+	favoriteServer := &http.Server{Addr: ":9092", Handler: mux}
 	g.Add(func() error {
-		mux := http.NewServeMux()
 		mux.Handle("/metrics", promhttp.HandlerFor(
 			rpc.PromRegistry,
 			promhttp.HandlerOpts{
 				EnableOpenMetrics: true,
 			},
 		))
+		mux.Handle("/healthz", server.HealthzHandler())
+		mux.Handle("/readyz", server.ReadyzHandler())
+		mux.Handle("/admin/jobs", jobs.StatusHandler())
 		return favoriteServer.ListenAndServe()
 	}, func(err error) {
 		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
@@ -66,28 +125,49 @@ func main() {
 	// 可以考虑超时强制退出,加一个 Timer
 	ctx := cr.Stop()
 	<-ctx.Done()
-}
 
-func initRegistry() *clientv3.Client {
-	cli, err := clientv3.New(clientv3.Config{
-		Endpoints:   []string{config.GetConf().ETCD.EndPoints},
-		DialTimeout: time.Second * 2,
-	})
-	if err != nil {
-		panic(err)
+	// 释放任务锁, 其他实例无需等待租约过期即可接管
+	if app.Locker != nil {
+		if err := app.Locker.Close(); err != nil {
+			log.Printf("failed to release job locks: %v", err)
+		}
 	}
-
-	return cli
 }
 
-func initCronJob(l *zap.Logger, svc *service.FavoriteServer) *cron.Cron {
+func initCronJob(l *zap.Logger, svc *service.FavoriteServer, locker scheduler.JobLocker, pauses scheduler.PauseStore) (*cron.Cron, *scheduler.CronJobBuilder) {
 	cr := cron.New(cron.WithSeconds())
 
-	job := scheduler.NewScheduler(svc)
-	_, err := cr.AddJob("0 0 */2 * * ?", scheduler.NewCronJobBuilder(l).Builder(job))
-	if err != nil {
+	builder := scheduler.NewCronJobBuilder(cr, l, rpc.PromRegistry, locker, pauses)
+
+	// 以下为默认调度规则, 可通过配置 jobs.<name> 覆盖
+	// 同步时记录点赞数快照, 小时粒度的历史精度取决于同步周期
+	builder.Register("0 0 */2 * * ?", func(opts ...scheduler.Option) job.Job {
+		return scheduler.NewScheduler(svc, opts...)
+	})
+	builder.Register("0 */5 * * * ?", func(opts ...scheduler.Option) job.Job {
+		return scheduler.NewEraseScheduler(svc, opts...)
+	})
+	builder.Register("0 0 3 * * ?", func(opts ...scheduler.Option) job.Job {
+		return scheduler.NewActionLogScheduler(svc, opts...)
+	})
+	builder.Register("0 30 4 * * ?", func(opts ...scheduler.Option) job.Job {
+		return scheduler.NewRelatedScheduler(svc, opts...)
+	})
+	builder.Register("0 10 0 * * ?", func(opts ...scheduler.Option) job.Job {
+		return scheduler.NewHistoryScheduler(svc, opts...)
+	})
+	builder.Register("*/30 * * * * ?", func(opts ...scheduler.Option) job.Job {
+		return scheduler.NewMetricsScheduler(svc, opts...)
+	})
+
+	if err := builder.Apply(config.GetConf().Jobs); err != nil {
 		panic(err)
 	}
+	config.OnChange(func(conf *config.Config) {
+		if err := builder.Apply(conf.Jobs); err != nil {
+			l.Error("reload job config failed", zap.Error(err))
+		}
+	})
 
-	return cr
+	return cr, builder
 }
@@ -0,0 +1,82 @@
+package antiabuse
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Action 一次点赞/取消点赞行为
+type Action struct {
+	UserId     int64
+	Biz        string
+	BizId      int64
+	ActionType int32
+	Time       time.Time
+	// SignupAt 账号注册时间, 秒级时间戳, 未知时为 0
+	SignupAt int64
+}
+
+// Scorer 单个检测规则, 返回 [0, 1] 之间的可疑分数
+// 实现方需要自行把本次行为记录到历史中, 以便后续请求参考
+type Scorer interface {
+	Name() string
+	Score(ctx context.Context, a Action) (float64, error)
+}
+
+// UserEraser 由保存了用户行为记录的规则实现, 用户注销时删除其全部记录
+type UserEraser interface {
+	EraseUser(ctx context.Context, uid int64) error
+}
+
+// Verdict 检测结果
+type Verdict struct {
+	Score      float64
+	Reasons    []string
+	Suspicious bool
+}
+
+// Pipeline 依次执行所有规则并累加分数, 超过阈值即认为可疑
+type Pipeline struct {
+	scorers   []Scorer
+	threshold float64
+}
+
+func NewPipeline(threshold float64, scorers ...Scorer) *Pipeline {
+	return &Pipeline{
+		scorers:   scorers,
+		threshold: threshold,
+	}
+}
+
+func (p *Pipeline) Evaluate(ctx context.Context, a Action) (Verdict, error) {
+	var v Verdict
+	for _, s := range p.scorers {
+		score, err := s.Score(ctx, a)
+		if err != nil {
+			return Verdict{}, err
+		}
+		if score > 0 {
+			v.Score += score
+			v.Reasons = append(v.Reasons, s.Name())
+		}
+	}
+	v.Suspicious = len(p.scorers) > 0 && v.Score >= p.threshold
+
+	return v, nil
+}
+
+// EraseUser 删除各规则保存的用户行为记录
+func (p *Pipeline) EraseUser(ctx context.Context, uid int64) error {
+	for _, s := range p.scorers {
+		e, ok := s.(UserEraser)
+		if !ok {
+			continue
+		}
+		if err := e.EraseUser(ctx, uid); err != nil {
+			return fmt.Errorf("%s: %w", s.Name(), err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,171 @@
+package antiabuse
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/crazyfrankie/favorite/pkg/constants"
+)
+
+const (
+	// 用户在本服务首次点赞的时间模板, 不代表账号年龄, 仅在注册时间未知时代替
+	firstLikeKey = "abuse:user:%d:first_like"
+	// 首次点赞时间的保留时长, 过期后再次点赞的用户会重新视为新账号
+	firstLikeRetention = 30 * 24 * time.Hour
+	// 内容维度的新账号点赞窗口 zset 模板
+	burstKey = "abuse:burst:%s:%d"
+	// 用户对单个内容的操作次数计数器模板
+	cycleKey = "abuse:cycle:%d:%s:%d"
+)
+
+// BurstScorer 检测大量新账号在短时间内集中点赞同一内容
+type BurstScorer struct {
+	cmd redis.Cmdable
+	// 统计窗口
+	window time.Duration
+	// 注册(或首次点赞)在该时间内的账号视为新账号
+	freshAge time.Duration
+	// 窗口内新账号点赞数达到该值时分数为 1
+	limit int64
+}
+
+func NewBurstScorer(cmd redis.Cmdable, window, freshAge time.Duration, limit int64) *BurstScorer {
+	return &BurstScorer{
+		cmd:      cmd,
+		window:   window,
+		freshAge: freshAge,
+		limit:    limit,
+	}
+}
+
+func (b *BurstScorer) Name() string {
+	return "fresh_account_burst"
+}
+
+func (b *BurstScorer) Score(ctx context.Context, a Action) (float64, error) {
+	if a.ActionType != constants.FavoriteActionType || b.limit <= 0 {
+		return 0, nil
+	}
+
+	now := a.Time.Unix()
+	signupAt := a.SignupAt
+	if signupAt <= 0 {
+		var err error
+		if signupAt, err = b.firstLike(ctx, a.UserId, now); err != nil {
+			return 0, err
+		}
+	}
+	if now-signupAt > int64(b.freshAge.Seconds()) {
+		return 0, nil
+	}
+
+	uid := strconv.FormatInt(a.UserId, 10)
+
+	key := fmt.Sprintf(burstKey, a.Biz, a.BizId)
+	pipe := b.cmd.TxPipeline()
+	pipe.ZRemRangeByScore(ctx, key, "-inf", strconv.FormatInt(now-int64(b.window.Seconds()), 10))
+	pipe.ZAdd(ctx, key, redis.Z{Score: float64(now), Member: uid})
+	card := pipe.ZCard(ctx, key)
+	pipe.Expire(ctx, key, b.window)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return 0, err
+	}
+
+	return ratio(card.Val(), b.limit), nil
+}
+
+// firstLike 获取用户在本服务首次点赞的时间, 首次点赞时记录为 now
+func (b *BurstScorer) firstLike(ctx context.Context, uid, now int64) (int64, error) {
+	key := fmt.Sprintf(firstLikeKey, uid)
+	ok, err := b.cmd.SetNX(ctx, key, now, max(firstLikeRetention, 2*b.freshAge)).Result()
+	if err != nil {
+		return 0, err
+	}
+	if ok {
+		return now, nil
+	}
+
+	return b.cmd.Get(ctx, key).Int64()
+}
+
+// EraseUser 删除用户的首次点赞时间, 并把用户从各内容的点赞窗口中移除
+func (b *BurstScorer) EraseUser(ctx context.Context, uid int64) error {
+	if err := b.cmd.Del(ctx, fmt.Sprintf(firstLikeKey, uid)).Err(); err != nil {
+		return err
+	}
+
+	member := strconv.FormatInt(uid, 10)
+	iter := b.cmd.Scan(ctx, 0, "abuse:burst:*", 100).Iterator()
+	for iter.Next(ctx) {
+		if err := b.cmd.ZRem(ctx, iter.Val(), member).Err(); err != nil {
+			return err
+		}
+	}
+
+	return iter.Err()
+}
+
+// CycleScorer 检测单个账号对同一内容反复点赞/取消点赞
+type CycleScorer struct {
+	cmd redis.Cmdable
+	// 统计窗口
+	window time.Duration
+	// 窗口内操作次数达到该值时分数为 1
+	limit int64
+}
+
+func NewCycleScorer(cmd redis.Cmdable, window time.Duration, limit int64) *CycleScorer {
+	return &CycleScorer{
+		cmd:    cmd,
+		window: window,
+		limit:  limit,
+	}
+}
+
+func (c *CycleScorer) Name() string {
+	return "like_unlike_cycle"
+}
+
+func (c *CycleScorer) Score(ctx context.Context, a Action) (float64, error) {
+	if c.limit <= 0 {
+		return 0, nil
+	}
+
+	key := fmt.Sprintf(cycleKey, a.UserId, a.Biz, a.BizId)
+	pipe := c.cmd.TxPipeline()
+	cnt := pipe.Incr(ctx, key)
+	pipe.ExpireNX(ctx, key, c.window)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return 0, err
+	}
+
+	// 第一次操作属于正常行为
+	return ratio(cnt.Val()-1, c.limit), nil
+}
+
+// EraseUser 删除用户对各内容的操作计数
+func (c *CycleScorer) EraseUser(ctx context.Context, uid int64) error {
+	iter := c.cmd.Scan(ctx, 0, fmt.Sprintf("abuse:cycle:%d:*", uid), 100).Iterator()
+	for iter.Next(ctx) {
+		if err := c.cmd.Del(ctx, iter.Val()).Err(); err != nil {
+			return err
+		}
+	}
+
+	return iter.Err()
+}
+
+func ratio(n, limit int64) float64 {
+	if n <= 0 {
+		return 0
+	}
+	if n >= limit {
+		return 1
+	}
+
+	return float64(n) / float64(limit)
+}
@@ -0,0 +1,95 @@
+package antiabuse
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/crazyfrankie/favorite/pkg/constants"
+)
+
+func newTestRedis(t *testing.T) (redis.Cmdable, *miniredis.Miniredis) {
+	t.Helper()
+
+	mr := miniredis.RunT(t)
+	cmd := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { _ = cmd.Close() })
+
+	return cmd, mr
+}
+
+func TestBurstScorerSignupAt(t *testing.T) {
+	ctx := context.Background()
+	cmd, mr := newTestRedis(t)
+	s := NewBurstScorer(cmd, time.Minute, 24*time.Hour, 2)
+
+	now := time.Now()
+	// 注册时间来自 token 时不记录首次点赞时间
+	old := Action{UserId: 1, Biz: "post", BizId: 1, ActionType: constants.FavoriteActionType, Time: now,
+		SignupAt: now.Add(-48 * time.Hour).Unix()}
+	score, err := s.Score(ctx, old)
+	require.NoError(t, err)
+	assert.Zero(t, score)
+	assert.False(t, mr.Exists(fmt.Sprintf(firstLikeKey, 1)))
+
+	fresh := Action{UserId: 2, Biz: "post", BizId: 1, ActionType: constants.FavoriteActionType, Time: now,
+		SignupAt: now.Add(-time.Hour).Unix()}
+	score, err = s.Score(ctx, fresh)
+	require.NoError(t, err)
+	assert.Equal(t, 0.5, score)
+}
+
+func TestBurstScorerFirstLike(t *testing.T) {
+	ctx := context.Background()
+	cmd, mr := newTestRedis(t)
+	s := NewBurstScorer(cmd, time.Minute, 24*time.Hour, 2)
+
+	now := time.Now()
+	a := Action{UserId: 1, Biz: "post", BizId: 1, ActionType: constants.FavoriteActionType, Time: now}
+	score, err := s.Score(ctx, a)
+	require.NoError(t, err)
+	assert.Equal(t, 0.5, score)
+
+	// 首次点赞时间按用户分 key 存储并带过期时间
+	key := fmt.Sprintf(firstLikeKey, 1)
+	assert.Equal(t, firstLikeRetention, mr.TTL(key))
+
+	// 首次点赞已超过 freshAge 的用户不再视为新账号
+	a.Time = now.Add(48 * time.Hour)
+	a.BizId = 2
+	score, err = s.Score(ctx, a)
+	require.NoError(t, err)
+	assert.Zero(t, score)
+}
+
+func TestPipelineEraseUser(t *testing.T) {
+	ctx := context.Background()
+	cmd, mr := newTestRedis(t)
+	p := NewPipeline(1,
+		NewBurstScorer(cmd, time.Minute, 24*time.Hour, 10),
+		NewCycleScorer(cmd, time.Minute, 10),
+	)
+
+	now := time.Now()
+	for _, uid := range []int64{1, 2} {
+		_, err := p.Evaluate(ctx, Action{UserId: uid, Biz: "post", BizId: 1, ActionType: constants.FavoriteActionType, Time: now})
+		require.NoError(t, err)
+	}
+
+	require.NoError(t, p.EraseUser(ctx, 1))
+
+	assert.False(t, mr.Exists(fmt.Sprintf(firstLikeKey, 1)))
+	assert.False(t, mr.Exists(fmt.Sprintf(cycleKey, 1, "post", 1)))
+	members, err := mr.ZMembers(fmt.Sprintf(burstKey, "post", 1))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"2"}, members)
+	// 其他用户的记录不受影响
+	assert.True(t, mr.Exists(fmt.Sprintf(firstLikeKey, 2)))
+	assert.True(t, mr.Exists(fmt.Sprintf(cycleKey, 2, "post", 1)))
+}
@@ -5,6 +5,8 @@ type UserFavorite struct {
 	Biz    string
 	BizId  int64
 	Status uint8
+	Ctime  int64
+	Utime  int64
 }
 
 type FavoriteCount struct {
@@ -12,3 +14,109 @@ type FavoriteCount struct {
 	Biz   string
 	BizId int64
 }
+
+// FlaggedFavorite 被反作弊标记的点赞, 对本人可见但不计入公开计数
+type FlaggedFavorite struct {
+	UserId    int64
+	Biz       string
+	BizId     int64
+	Score     float64
+	Reasons   []string
+	FlaggedAt int64
+}
+
+// EraseTask 用户点赞数据擦除任务
+type EraseTask struct {
+	UserId     int64
+	Status     uint8
+	Processed  int64
+	Error      string
+	Ctime      int64
+	FinishedAt int64
+}
+
+// UnFavorite 用户的一条取消点赞记录
+type UnFavorite struct {
+	Biz       string
+	BizId     int64
+	UnlikedAt int64
+}
+
+// ActionLog 一条点赞操作流水
+type ActionLog struct {
+	UserId     int64
+	Biz        string
+	BizId      int64
+	ActionType int32
+	CallerId   int64
+	ClientIp   string
+	TraceId    string
+	Ctime      int64
+}
+
+// ActionLogQuery 操作流水查询条件, UserId 与 Biz/BizId 至少指定一组
+type ActionLogQuery struct {
+	UserId int64
+	Biz    string
+	BizId  int64
+	// 毫秒时间戳, 左闭右开
+	Start  int64
+	End    int64
+	Offset int
+	Limit  int
+}
+
+// ImportRecord 从其他系统迁移的一条历史点赞
+type ImportRecord struct {
+	UserId  int64
+	Biz     string
+	BizId   int64
+	LikedAt int64
+}
+
+// ImportResult 导入结果, 单批失败不会中断整个导入
+type ImportResult struct {
+	Received   int64
+	Imported   int64
+	Duplicates int64
+	Invalid    int64
+	Errors     []ImportBatchError
+}
+
+type ImportBatchError struct {
+	Batch int64
+	Err   string
+}
+
+// ContentSnapshot 单个内容在缓存与数据库中的点赞数据, 用于排查不一致
+type ContentSnapshot struct {
+	CacheCount int64
+	DBCount    int64
+	CacheUsers []int64
+	DBUsers    []int64
+}
+
+// UserSnapshot 用户在缓存与数据库中的点赞记录, 元素格式为 "{biz}:{bizId}"
+type UserSnapshot struct {
+	CacheItems []string
+	DBItems    []string
+}
+
+// CommonFavorite 两个用户都点赞了的内容, LikedAt 为两人中较晚的点赞时间
+type CommonFavorite struct {
+	Biz     string
+	BizId   int64
+	LikedAt int64
+}
+
+// RelatedItem 与某个内容相关的内容, Score 为两者点赞用户集合的 Jaccard 相似度
+type RelatedItem struct {
+	BizId int64
+	Score float64
+}
+
+// CountPoint 点赞数时间序列中的一个点, Time 为时间段起点
+type CountPoint struct {
+	Time  int64
+	Count int64
+}
@@ -0,0 +1,268 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+
+	"github.com/crazyfrankie/favorite/internal/biz/metrics"
+	"github.com/crazyfrankie/favorite/internal/biz/repository"
+)
+
+const (
+	// 业务方发布内容事件的 stream, 消息字段: type, biz, biz_id, owner_id(仅 created)
+	contentStream = "favorite:events:content"
+	contentGroup  = "favorite"
+	// 多次处理失败的消息转入死信 stream, 保留原字段并附加 source_id
+	contentDeadStream = "favorite:events:content:dead"
+
+	ContentCreated = "created"
+	ContentDeleted = "deleted"
+
+	// 消息最多投递次数, 超过后转入死信
+	maxDeliveries = 5
+	// 失败重试的退避时间, 按投递次数翻倍
+	retryBackoff    = 10 * time.Second
+	maxRetryBackoff = 10 * time.Minute
+	// 检查待重试消息的间隔
+	retryInterval = 5 * time.Second
+)
+
+// ContentConsumer 消费业务方的内容事件, 内容发布时登记归属, 删除时自动清理对应的点赞数据
+type ContentConsumer struct {
+	cmd      redis.Cmdable
+	repo     *repository.FavoriteRepo
+	consumer string
+}
+
+func NewContentConsumer(cmd redis.Cmdable, repo *repository.FavoriteRepo) *ContentConsumer {
+	consumer, err := os.Hostname()
+	if err != nil {
+		consumer = "favorite-" + strconv.Itoa(os.Getpid())
+	}
+
+	return &ContentConsumer{
+		cmd:      cmd,
+		repo:     repo,
+		consumer: consumer,
+	}
+}
+
+// Start 阻塞消费直到 ctx 被取消
+func (c *ContentConsumer) Start(ctx context.Context) error {
+	err := c.cmd.XGroupCreateMkStream(ctx, contentStream, contentGroup, "0").Err()
+	if err != nil && !strings.HasPrefix(err.Error(), "BUSYGROUP") {
+		return err
+	}
+
+	// 先过一遍上次退出前未确认的消息, 处理失败的留给 retry, 之后只消费新消息
+	if err := c.drainBacklog(ctx); err != nil {
+		return err
+	}
+
+	lastRetry := time.Now()
+	for {
+		if time.Since(lastRetry) >= retryInterval {
+			c.retry(ctx)
+			lastRetry = time.Now()
+		}
+
+		streams, err := c.cmd.XReadGroup(ctx, &redis.XReadGroupArgs{
+			Group:    contentGroup,
+			Consumer: c.consumer,
+			Streams:  []string{contentStream, ">"},
+			Count:    10,
+			Block:    retryInterval,
+		}).Result()
+		if ctx.Err() != nil {
+			return nil
+		}
+		if errors.Is(err, redis.Nil) {
+			continue
+		}
+		if err != nil {
+			zap.L().Error("read content events failed", zap.Error(err))
+			time.Sleep(time.Second)
+			continue
+		}
+
+		c.reportDepth(ctx)
+
+		for _, s := range streams {
+			for _, msg := range s.Messages {
+				c.process(ctx, msg)
+			}
+		}
+	}
+}
+
+// drainBacklog 按 ID 递增遍历一次本消费者已投递未确认的消息
+func (c *ContentConsumer) drainBacklog(ctx context.Context) error {
+	id := "0"
+	for {
+		streams, err := c.cmd.XReadGroup(ctx, &redis.XReadGroupArgs{
+			Group:    contentGroup,
+			Consumer: c.consumer,
+			Streams:  []string{contentStream, id},
+			Count:    10,
+			Block:    -1,
+		}).Result()
+		if ctx.Err() != nil || errors.Is(err, redis.Nil) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		var n int
+		for _, s := range streams {
+			for _, msg := range s.Messages {
+				n++
+				id = msg.ID
+				c.process(ctx, msg)
+			}
+		}
+		if n == 0 {
+			return nil
+		}
+	}
+}
+
+// retry 认领空闲时间超过退避时间的未确认消息重新处理, 包括已下线消费者遗留的消息;
+// 投递次数达到上限的消息转入死信
+func (c *ContentConsumer) retry(ctx context.Context) {
+	pending, err := c.cmd.XPendingExt(ctx, &redis.XPendingExtArgs{
+		Stream: contentStream,
+		Group:  contentGroup,
+		Idle:   retryBackoff,
+		Start:  "-",
+		End:    "+",
+		Count:  100,
+	}).Result()
+	if err != nil {
+		zap.L().Error("list pending content events failed", zap.Error(err))
+		return
+	}
+
+	for _, p := range pending {
+		if p.Idle < backoff(p.RetryCount) {
+			continue
+		}
+
+		msgs, err := c.cmd.XClaim(ctx, &redis.XClaimArgs{
+			Stream:   contentStream,
+			Group:    contentGroup,
+			Consumer: c.consumer,
+			MinIdle:  backoff(p.RetryCount),
+			Messages: []string{p.ID},
+		}).Result()
+		if err != nil {
+			zap.L().Error("claim content event failed", zap.String("id", p.ID), zap.Error(err))
+			continue
+		}
+
+		for _, msg := range msgs {
+			if p.RetryCount >= maxDeliveries {
+				c.deadLetter(ctx, msg)
+				continue
+			}
+			c.process(ctx, msg)
+		}
+	}
+}
+
+// process 处理成功后确认消息, 失败时保持未确认, 由 retry 退避重试
+func (c *ContentConsumer) process(ctx context.Context, msg redis.XMessage) {
+	// 已被裁剪的消息认领后没有字段, 直接确认
+	if len(msg.Values) > 0 {
+		if err := c.handle(ctx, msg); err != nil {
+			zap.L().Error("handle content event failed", zap.String("id", msg.ID), zap.Error(err))
+			return
+		}
+	}
+
+	c.ack(ctx, msg.ID)
+}
+
+func (c *ContentConsumer) handle(ctx context.Context, msg redis.XMessage) error {
+	typ, _ := msg.Values["type"].(string)
+	biz, _ := msg.Values["biz"].(string)
+	bizIdStr, _ := msg.Values["biz_id"].(string)
+	bizId, err := strconv.ParseInt(bizIdStr, 10, 64)
+	if biz == "" || err != nil {
+		return nil
+	}
+
+	switch typ {
+	case ContentDeleted:
+		if _, err := c.repo.PurgeContent(ctx, biz, bizId); err != nil {
+			return fmt.Errorf("purge content %s:%d: %w", biz, bizId, err)
+		}
+	case ContentCreated:
+		ownerStr, _ := msg.Values["owner_id"].(string)
+		ownerId, err := strconv.ParseInt(ownerStr, 10, 64)
+		if err != nil || ownerId <= 0 {
+			return nil
+		}
+		if err := c.repo.RegisterContentOwner(ctx, biz, bizId, ownerId); err != nil {
+			return fmt.Errorf("register owner of %s:%d: %w", biz, bizId, err)
+		}
+	}
+
+	return nil
+}
+
+func (c *ContentConsumer) deadLetter(ctx context.Context, msg redis.XMessage) {
+	values := make(map[string]any, len(msg.Values)+1)
+	for k, v := range msg.Values {
+		values[k] = v
+	}
+	values["source_id"] = msg.ID
+
+	if err := c.cmd.XAdd(ctx, &redis.XAddArgs{Stream: contentDeadStream, Values: values}).Err(); err != nil {
+		zap.L().Error("dead-letter content event failed", zap.String("id", msg.ID), zap.Error(err))
+		return
+	}
+	zap.L().Warn("content event moved to dead letter", zap.String("id", msg.ID))
+	c.ack(ctx, msg.ID)
+}
+
+func (c *ContentConsumer) ack(ctx context.Context, id string) {
+	if err := c.cmd.XAck(ctx, contentStream, contentGroup, id).Err(); err != nil {
+		zap.L().Error("ack content event failed", zap.String("id", id), zap.Error(err))
+	}
+}
+
+// backoff 第 n 次投递失败后的重试间隔
+func backoff(deliveries int64) time.Duration {
+	d := retryBackoff
+	for i := int64(1); i < deliveries && d < maxRetryBackoff; i++ {
+		d *= 2
+	}
+
+	return min(d, maxRetryBackoff)
+}
+
+// reportDepth 上报消费组中尚未处理的消息数: 已投递未确认 + 尚未投递
+func (c *ContentConsumer) reportDepth(ctx context.Context) {
+	groups, err := c.cmd.XInfoGroups(ctx, contentStream).Result()
+	if err != nil {
+		return
+	}
+	for _, g := range groups {
+		if g.Name == contentGroup {
+			metrics.QueueDepth.WithLabelValues("content_events").Set(float64(g.Pending + max(g.Lag, 0)))
+			break
+		}
+	}
+	if dead, err := c.cmd.XLen(ctx, contentDeadStream).Result(); err == nil {
+		metrics.QueueDepth.WithLabelValues("content_events_dead").Set(float64(dead))
+	}
+}
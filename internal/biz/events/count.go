@@ -0,0 +1,139 @@
+package events
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/crazyfrankie/favorite/internal/biz/domain"
+	"github.com/crazyfrankie/favorite/internal/biz/repository"
+)
+
+// CountHub 每个实例只订阅一次 Redis 的点赞数变化, 再分发给本实例上的 WatchFavoriteCount 订阅者
+type CountHub struct {
+	repo *repository.FavoriteRepo
+
+	mu   sync.RWMutex
+	subs map[string]map[*CountSubscription]struct{}
+}
+
+func NewCountHub(repo *repository.FavoriteRepo) *CountHub {
+	return &CountHub{
+		repo: repo,
+		subs: make(map[string]map[*CountSubscription]struct{}),
+	}
+}
+
+// Start 阻塞订阅直到 ctx 被取消, 订阅异常断开时自动重试
+func (h *CountHub) Start(ctx context.Context) error {
+	for {
+		err := h.repo.WatchCounts(ctx, h.dispatch)
+		if ctx.Err() != nil {
+			return nil
+		}
+		if err != nil {
+			zap.L().Error("watch favorite counts failed", zap.Error(err))
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(time.Second):
+		}
+	}
+}
+
+// Subscribe 订阅指定内容的点赞数变化, 使用完毕后需调用 Close
+func (h *CountHub) Subscribe(biz string, bizIds []int64) *CountSubscription {
+	s := &CountSubscription{
+		hub:     h,
+		pending: make(map[string]domain.FavoriteCount),
+		notify:  make(chan struct{}, 1),
+	}
+	for _, id := range bizIds {
+		s.members = append(s.members, countMember(biz, id))
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for _, m := range s.members {
+		if h.subs[m] == nil {
+			h.subs[m] = make(map[*CountSubscription]struct{})
+		}
+		h.subs[m][s] = struct{}{}
+	}
+
+	return s
+}
+
+func (h *CountHub) dispatch(cnt domain.FavoriteCount) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	for s := range h.subs[countMember(cnt.Biz, cnt.BizId)] {
+		s.update(cnt)
+	}
+}
+
+func (h *CountHub) remove(s *CountSubscription) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for _, m := range s.members {
+		delete(h.subs[m], s)
+		if len(h.subs[m]) == 0 {
+			delete(h.subs, m)
+		}
+	}
+}
+
+// CountSubscription 单个订阅者, 同一内容在两次读取之间的多次变化只保留最新值
+type CountSubscription struct {
+	hub     *CountHub
+	members []string
+
+	mu      sync.Mutex
+	pending map[string]domain.FavoriteCount
+	notify  chan struct{}
+}
+
+// Notify 有未读取的变化时可读
+func (s *CountSubscription) Notify() <-chan struct{} {
+	return s.notify
+}
+
+// Drain 取出自上次读取以来发生变化的内容的最新点赞数
+func (s *CountSubscription) Drain() []domain.FavoriteCount {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	res := make([]domain.FavoriteCount, 0, len(s.pending))
+	for _, cnt := range s.pending {
+		res = append(res, cnt)
+	}
+	clear(s.pending)
+
+	return res
+}
+
+func (s *CountSubscription) Close() {
+	s.hub.remove(s)
+}
+
+func (s *CountSubscription) update(cnt domain.FavoriteCount) {
+	s.mu.Lock()
+	s.pending[countMember(cnt.Biz, cnt.BizId)] = cnt
+	s.mu.Unlock()
+
+	select {
+	case s.notify <- struct{}{}:
+	default:
+	}
+}
+
+func countMember(biz string, bizId int64) string {
+	return fmt.Sprintf("%s:%d", biz, bizId)
+}
@@ -0,0 +1,86 @@
+package metrics
+
+import (
+	"slices"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/crazyfrankie/favorite/internal/config"
+)
+
+// otherBiz 未在配置中列出的业务统一使用的 biz 标签
+const otherBiz = "other"
+
+var (
+	// Actions 点赞/取消点赞次数, 通过 rate() 得到每秒点赞数
+	Actions = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "favorite",
+		Name:      "actions_total",
+		Help:      "Number of successful favorite actions.",
+	}, []string{"biz", "action"})
+
+	// CacheRequests FavoriteCache 各方法的命中情况, result 为 hit、miss 或 empty,
+	// empty 表示数据本身为空(如内容没有点赞), 不属于缓存未命中
+	CacheRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "favorite",
+		Subsystem: "cache",
+		Name:      "requests_total",
+		Help:      "Number of FavoriteCache lookups by method and result.",
+	}, []string{"method", "result"})
+
+	// ActiveLikers 最近一段时间内点赞过的去重用户数
+	ActiveLikers = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: "favorite",
+		Name:      "active_likers",
+		Help:      "Approximate number of distinct users who liked something in the last window.",
+	})
+
+	// CounterDrift 抽样内容中点赞计数与点赞用户集合大小之差的绝对值之和
+	CounterDrift = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "favorite",
+		Name:      "counter_drift",
+		Help:      "Sum of absolute differences between counters and liker sets over sampled items.",
+	}, []string{"biz"})
+
+	// SyncLag 距上次成功将 Redis 点赞数同步到 MySQL 的秒数
+	SyncLag = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: "favorite",
+		Name:      "sync_lag_seconds",
+		Help:      "Seconds since the last successful Redis to MySQL count sync.",
+	})
+
+	// QueueDepth 等待异步处理的任务数
+	QueueDepth = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "favorite",
+		Name:      "async_queue_depth",
+		Help:      "Number of pending items in asynchronous write queues.",
+	}, []string{"queue"})
+)
+
+// Collectors 需要注册到 rpc.PromRegistry 的领域指标
+func Collectors() []prometheus.Collector {
+	return []prometheus.Collector{Actions, CacheRequests, ActiveLikers, CounterDrift, SyncLag, QueueDepth}
+}
+
+// CacheResult 记录一次缓存查询结果
+func CacheResult(method string, hit bool) {
+	result := "miss"
+	if hit {
+		result = "hit"
+	}
+	CacheRequests.WithLabelValues(method, result).Inc()
+}
+
+// CacheEmpty 记录一次结果为空的缓存查询
+func CacheEmpty(method string) {
+	CacheRequests.WithLabelValues(method, "empty").Inc()
+}
+
+// BizLabel 将 biz 映射为指标标签, 未配置的业务归为 other
+func BizLabel(biz string) string {
+	if slices.Contains(config.GetConf().Metrics.Biz, biz) {
+		return biz
+	}
+
+	return otherBiz
+}
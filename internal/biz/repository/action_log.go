@@ -0,0 +1,69 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/crazyfrankie/favorite/internal/biz/domain"
+	"github.com/crazyfrankie/favorite/internal/biz/repository/dao"
+)
+
+// CreateActionLog 追加一条点赞操作流水
+func (r *FavoriteRepo) CreateActionLog(ctx context.Context, log domain.ActionLog) error {
+	return r.write.CreateActionLog(ctx, dao.FavoriteActionLog{
+		UserId:     log.UserId,
+		Biz:        log.Biz,
+		BizId:      log.BizId,
+		ActionType: uint8(log.ActionType),
+		CallerId:   log.CallerId,
+		ClientIp:   log.ClientIp,
+		TraceId:    log.TraceId,
+		Ctime:      log.Ctime,
+	})
+}
+
+// ListActionLogs 按时间范围查询操作流水
+func (r *FavoriteRepo) ListActionLogs(ctx context.Context, q domain.ActionLogQuery) ([]domain.ActionLog, error) {
+	logs, err := r.read.ListActionLogs(ctx, q.UserId, q.Biz, q.BizId, q.Start, q.End, q.Offset, q.Limit)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]domain.ActionLog, 0, len(logs))
+	for _, l := range logs {
+		res = append(res, actionLogToDomain(l))
+	}
+
+	return res, nil
+}
+
+// LastActionLog 获取用户对某个内容在 at 时刻之前的最后一次操作, 不存在时返回 false
+func (r *FavoriteRepo) LastActionLog(ctx context.Context, uid int64, biz string, bizId, at int64) (domain.ActionLog, bool, error) {
+	log, err := r.read.LastActionLog(ctx, uid, biz, bizId, at)
+	if errors.Is(err, dao.ErrRecordNotFound) {
+		return domain.ActionLog{}, false, nil
+	}
+	if err != nil {
+		return domain.ActionLog{}, false, err
+	}
+
+	return actionLogToDomain(log), true, nil
+}
+
+// EnsureActionLogPartitions 预建未来几个月的流水分区
+func (r *FavoriteRepo) EnsureActionLogPartitions(ctx context.Context, months int) error {
+	return r.write.EnsureActionLogPartitions(ctx, months)
+}
+
+func actionLogToDomain(l dao.FavoriteActionLog) domain.ActionLog {
+	return domain.ActionLog{
+		UserId:     l.UserId,
+		Biz:        l.Biz,
+		BizId:      l.BizId,
+		ActionType: int32(l.ActionType),
+		CallerId:   l.CallerId,
+		ClientIp:   l.ClientIp,
+		TraceId:    l.TraceId,
+		Ctime:      l.Ctime,
+	}
+}
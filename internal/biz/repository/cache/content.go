@@ -0,0 +1,164 @@
+package cache
+
+import (
+	"context"
+	_ "embed"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+//go:embed lua/delete_content.lua
+var luaDeleteContent string
+
+// purgedRetention 已删除内容标记的保留时长, 清理完成后只用于拦截迟到的点赞及残留的列表记录
+const purgedRetention = 30 * 24 * time.Hour
+
+// MarkContentPurged 标记内容已被删除, 后续点赞及列表查询会过滤该内容
+func (c *FavoriteCache) MarkContentPurged(ctx context.Context, biz string, bizId int64) error {
+	keys := c.keys()
+
+	return c.cmd.Set(ctx, fmt.Sprintf(keys.purgedKey, biz, bizId), 1, purgedRetention).Err()
+}
+
+// IsContentPurged 内容是否已被删除
+func (c *FavoriteCache) IsContentPurged(ctx context.Context, biz string, bizId int64) (bool, error) {
+	keys := c.keys()
+
+	n, err := c.cmd.Exists(ctx, fmt.Sprintf(keys.purgedKey, biz, bizId)).Result()
+
+	return n > 0, err
+}
+
+// FilterPurged 过滤掉已被删除的内容, members 格式为 "{biz}:{bizId}"
+func (c *FavoriteCache) FilterPurged(ctx context.Context, members []string) ([]string, error) {
+	if len(members) == 0 {
+		return members, nil
+	}
+	keys := c.keys()
+
+	pipe := c.cmd.Pipeline()
+	purged := make([]*redis.IntCmd, len(members))
+	for i, m := range members {
+		idx := strings.LastIndexByte(m, ':')
+		if idx < 0 {
+			continue
+		}
+		bizId, err := strconv.ParseInt(m[idx+1:], 10, 64)
+		if err != nil {
+			continue
+		}
+		purged[i] = pipe.Exists(ctx, fmt.Sprintf(keys.purgedKey, m[:idx], bizId))
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		return nil, err
+	}
+
+	res := make([]string, 0, len(members))
+	for i, m := range members {
+		if purged[i] == nil || purged[i].Val() == 0 {
+			res = append(res, m)
+		}
+	}
+
+	return res, nil
+}
+
+// BizUsersBatch 从内容的点赞用户集合中读取最多 count 个用户, 用于分批处理大集合
+func (c *FavoriteCache) BizUsersBatch(ctx context.Context, biz string, bizId, count int64) ([]int64, error) {
+	keys := c.keys()
+
+	res, err := c.cmd.SRandMemberN(ctx, fmt.Sprintf(keys.bizUserKey, biz, bizId), count).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	users := make([]int64, 0, len(res))
+	for _, v := range res {
+		uid, _ := strconv.ParseInt(v, 10, 64)
+		users = append(users, uid)
+	}
+
+	return users, nil
+}
+
+// RemoveFromUsers 将内容从这些用户的点赞记录中移除
+func (c *FavoriteCache) RemoveFromUsers(ctx context.Context, biz string, bizId int64, uids []int64) error {
+	keys := c.keys()
+
+	member := fmt.Sprintf("%s:%d", biz, bizId)
+	pipe := c.cmd.Pipeline()
+	for _, uid := range uids {
+		pipe.ZRem(ctx, fmt.Sprintf(keys.userFavoriteKey, uid), member)
+	}
+	_, err := pipe.Exec(ctx)
+
+	return err
+}
+
+// RemoveBizUsers 从内容的点赞用户集合中移除这些用户
+func (c *FavoriteCache) RemoveBizUsers(ctx context.Context, biz string, bizId int64, uids []int64) error {
+	if len(uids) == 0 {
+		return nil
+	}
+	keys := c.keys()
+
+	members := make([]any, 0, len(uids))
+	for _, uid := range uids {
+		members = append(members, uid)
+	}
+
+	return c.cmd.SRem(ctx, fmt.Sprintf(keys.bizUserKey, biz, bizId), members...).Err()
+}
+
+// PurgeFlaggedContent 清除内容下所有被标记的点赞, 返回清除数量
+func (c *FavoriteCache) PurgeFlaggedContent(ctx context.Context, biz string, bizId int64) (int64, error) {
+	keys := c.keys()
+
+	prefix := fmt.Sprintf("%s:%d:", biz, bizId)
+	var (
+		cursor uint64
+		purged int64
+	)
+	for {
+		res, next, err := c.cmd.ZScan(ctx, keys.flaggedKey, cursor, prefix+"*", 100).Result()
+		if err != nil {
+			return purged, err
+		}
+
+		// ZSCAN 返回 member, score 交替排列
+		for i := 0; i < len(res); i += 2 {
+			uid, err := strconv.ParseInt(strings.TrimPrefix(res[i], prefix), 10, 64)
+			if err != nil {
+				continue
+			}
+			ok, err := c.PurgeFlaggedFavorite(ctx, biz, bizId, uid)
+			if err != nil {
+				return purged, err
+			}
+			if ok {
+				purged++
+			}
+		}
+
+		cursor = next
+		if cursor == 0 {
+			return purged, nil
+		}
+	}
+}
+
+// DeleteContent 删除内容的计数、点赞用户集合及归属, 并从创作者获赞数中扣除
+func (c *FavoriteCache) DeleteContent(ctx context.Context, biz string, bizId int64) error {
+	keys := c.keys()
+
+	return c.cmd.Eval(ctx, luaDeleteContent, []string{
+		keys.countKey,
+		fmt.Sprintf(keys.bizUserKey, biz, bizId),
+		keys.ownerKey,
+		keys.creatorCountKey,
+	}, biz, fmt.Sprintf("%s:%d", biz, bizId), bizId, keys.countChannel).Err()
+}
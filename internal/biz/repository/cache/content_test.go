@@ -0,0 +1,81 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeleteContent(t *testing.T) {
+	ctx := context.Background()
+	c, mr := newTestCache(t)
+	keys := c.keys()
+
+	require.NoError(t, c.RegisterContentOwner(ctx, "post", 1, 100))
+	require.NoError(t, c.RegisterContentOwner(ctx, "post", 2, 100))
+	require.NoError(t, c.CreateFavorite(ctx, "post", 1, 10))
+	require.NoError(t, c.CreateFavorite(ctx, "post", 1, 11))
+	require.NoError(t, c.CreateFavorite(ctx, "post", 2, 10))
+
+	require.NoError(t, c.DeleteContent(ctx, "post", 1))
+
+	// 只扣除被删除内容的点赞数, 其他内容不受影响
+	assert.Equal(t, "", mr.HGet(keys.countKey, "post:1"))
+	assert.Equal(t, "1", mr.HGet(keys.countKey, "post:2"))
+	assert.Equal(t, "", mr.HGet(keys.ownerKey, "post:1"))
+	assert.Equal(t, "1", mr.HGet(keys.creatorCountKey, "post:100"))
+	assert.False(t, mr.Exists(fmt.Sprintf(keys.bizUserKey, "post", 1)))
+
+	// 重复删除不会再次扣除
+	require.NoError(t, c.DeleteContent(ctx, "post", 1))
+	assert.Equal(t, "1", mr.HGet(keys.creatorCountKey, "post:100"))
+}
+
+func TestMarkContentPurged(t *testing.T) {
+	ctx := context.Background()
+	c, mr := newTestCache(t)
+
+	require.NoError(t, c.MarkContentPurged(ctx, "post", 1))
+
+	ok, err := c.IsContentPurged(ctx, "post", 1)
+	require.NoError(t, err)
+	assert.True(t, ok)
+	alive, err := c.FilterPurged(ctx, []string{"post:1", "post:2"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"post:2"}, alive)
+
+	// 标记到期后自动清除, 不会无限增长
+	mr.FastForward(purgedRetention)
+	ok, err = c.IsContentPurged(ctx, "post", 1)
+	require.NoError(t, err)
+	assert.False(t, ok)
+}
+
+func TestBizUsersBatch(t *testing.T) {
+	ctx := context.Background()
+	c, _ := newTestCache(t)
+	keys := c.keys()
+
+	require.NoError(t, c.CreateFavorite(ctx, "post", 1, 10))
+	require.NoError(t, c.CreateFavorite(ctx, "post", 1, 11))
+
+	// 读取不会移除用户, 点赞记录清理完成后才移出集合
+	uids, err := c.BizUsersBatch(ctx, "post", 1, 10)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []int64{10, 11}, uids)
+	uids, err = c.BizUsersBatch(ctx, "post", 1, 10)
+	require.NoError(t, err)
+	assert.Len(t, uids, 2)
+
+	require.NoError(t, c.RemoveFromUsers(ctx, "post", 1, uids))
+	require.NoError(t, c.RemoveBizUsers(ctx, "post", 1, uids))
+	uids, err = c.BizUsersBatch(ctx, "post", 1, 10)
+	require.NoError(t, err)
+	assert.Empty(t, uids)
+	n, err := c.cmd.ZCard(ctx, fmt.Sprintf(keys.userFavoriteKey, 10)).Result()
+	require.NoError(t, err)
+	assert.Zero(t, n)
+}
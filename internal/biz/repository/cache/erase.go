@@ -0,0 +1,25 @@
+package cache
+
+import (
+	"context"
+	_ "embed"
+	"fmt"
+)
+
+//go:embed lua/erase_favorite.lua
+var luaEraseFavorite string
+
+// EraseFavorite 彻底擦除用户对某个内容的点赞, 可重复执行
+func (c *FavoriteCache) EraseFavorite(ctx context.Context, biz string, bizId, uid int64) error {
+	keys := c.keys()
+
+	return c.cmd.Eval(ctx, luaEraseFavorite, []string{
+		fmt.Sprintf(keys.bizUserKey, biz, bizId),
+		keys.countKey,
+		fmt.Sprintf(keys.userFavoriteKey, uid),
+		keys.flaggedKey,
+		keys.flaggedDetailKey,
+		keys.ownerKey,
+		keys.creatorCountKey,
+	}, uid, fmt.Sprintf("%s:%d", biz, bizId), flaggedMember(biz, bizId, uid), biz, bizId, keys.countChannel).Err()
+}
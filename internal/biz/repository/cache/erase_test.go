@@ -0,0 +1,67 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestCache(t *testing.T) (*FavoriteCache, *miniredis.Miniredis) {
+	t.Helper()
+
+	mr := miniredis.RunT(t)
+	cmd := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { _ = cmd.Close() })
+
+	return NewFavoriteCache(cmd), mr
+}
+
+func TestEraseFavorite(t *testing.T) {
+	ctx := context.Background()
+	c, mr := newTestCache(t)
+	keys := c.keys()
+
+	require.NoError(t, c.RegisterContentOwner(ctx, "post", 1, 100))
+	require.NoError(t, c.CreateFavorite(ctx, "post", 1, 10))
+	require.NoError(t, c.CreateFavorite(ctx, "post", 1, 11))
+
+	require.NoError(t, c.EraseFavorite(ctx, "post", 1, 10))
+
+	assert.Equal(t, "1", mr.HGet(keys.countKey, "post:1"))
+	assert.Equal(t, "1", mr.HGet(keys.creatorCountKey, "post:100"))
+	users, err := mr.SMembers(fmt.Sprintf(keys.bizUserKey, "post", 1))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"11"}, users)
+	assert.False(t, mr.Exists(fmt.Sprintf(keys.userFavoriteKey, 10)))
+
+	// 重复执行不会再次递减计数
+	require.NoError(t, c.EraseFavorite(ctx, "post", 1, 10))
+	assert.Equal(t, "1", mr.HGet(keys.countKey, "post:1"))
+	assert.Equal(t, "1", mr.HGet(keys.creatorCountKey, "post:100"))
+}
+
+func TestEraseFavoriteFlagged(t *testing.T) {
+	ctx := context.Background()
+	c, mr := newTestCache(t)
+	keys := c.keys()
+
+	member := flaggedMember("post", 1, 10)
+	_, err := mr.ZAdd(keys.flaggedKey, 1, member)
+	require.NoError(t, err)
+	mr.HSet(keys.flaggedDetailKey, member, "{}")
+	_, err = mr.ZAdd(fmt.Sprintf(keys.userFavoriteKey, 10), 1, "post:1")
+	require.NoError(t, err)
+
+	require.NoError(t, c.EraseFavorite(ctx, "post", 1, 10))
+
+	// 被标记的点赞未计入公开计数, 擦除时不应产生计数
+	assert.False(t, mr.Exists(keys.countKey))
+	assert.False(t, mr.Exists(keys.flaggedKey))
+	assert.False(t, mr.Exists(keys.flaggedDetailKey))
+	assert.False(t, mr.Exists(fmt.Sprintf(keys.userFavoriteKey, 10)))
+}
@@ -0,0 +1,67 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/crazyfrankie/favorite/internal/biz/domain"
+)
+
+// ImportFavorites 将导入的历史点赞写入用户点赞记录及内容点赞用户集合
+func (c *FavoriteCache) ImportFavorites(ctx context.Context, records []domain.ImportRecord) error {
+	keys := c.keys()
+
+	pipe := c.cmd.Pipeline()
+	for _, r := range records {
+		member := fmt.Sprintf("%s:%d", r.Biz, r.BizId)
+		userKey := fmt.Sprintf(keys.userFavoriteKey, r.UserId)
+
+		pipe.SAdd(ctx, keys.bizTypesKey, r.Biz)
+		pipe.SAdd(ctx, fmt.Sprintf(keys.bizUserKey, r.Biz, r.BizId), r.UserId)
+		pipe.ZAdd(ctx, userKey, redis.Z{Score: float64(r.LikedAt), Member: member})
+		pipe.Expire(ctx, userKey, userRecordTTL)
+	}
+	_, err := pipe.Exec(ctx)
+
+	return err
+}
+
+// SetFavoriteCounts 用数据库中的点赞数覆盖缓存计数
+func (c *FavoriteCache) SetFavoriteCounts(ctx context.Context, counts []domain.FavoriteCount) error {
+	if len(counts) == 0 {
+		return nil
+	}
+	keys := c.keys()
+
+	members := make([]string, 0, len(counts))
+	fields := make([]any, 0, len(counts)*2)
+	for _, cnt := range counts {
+		member := fmt.Sprintf("%s:%d", cnt.Biz, cnt.BizId)
+		members = append(members, member)
+		fields = append(fields, member, cnt.Count)
+	}
+
+	// 覆盖前的计数与新计数之差同步到创作者获赞数
+	owners, err := c.contentOwners(ctx, members)
+	if err != nil {
+		return err
+	}
+	olds, err := c.BatchFavoriteCount(ctx, counts)
+	if err != nil {
+		return err
+	}
+
+	pipe := c.cmd.TxPipeline()
+	pipe.HSet(ctx, keys.countKey, fields...)
+	for i, cnt := range counts {
+		if delta := cnt.Count - olds[i]; owners[i] > 0 && delta != 0 {
+			pipe.HIncrBy(ctx, keys.creatorCountKey, creatorField(cnt.Biz, owners[i]), delta)
+		}
+	}
+	c.publishCounts(ctx, pipe, counts...)
+	_, err = pipe.Exec(ctx)
+
+	return err
+}
@@ -2,6 +2,8 @@ package cache
 
 import (
 	"context"
+	_ "embed"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"sort"
@@ -12,6 +14,7 @@ import (
 	"github.com/redis/go-redis/v9"
 
 	"github.com/crazyfrankie/favorite/internal/biz/domain"
+	"github.com/crazyfrankie/favorite/internal/biz/metrics"
 )
 
 var (
@@ -19,6 +22,16 @@ var (
 	ErrNotFound      = errors.New("favorite not found")
 )
 
+// userRecordTTL 用户点赞/取消点赞记录的过期时间
+const userRecordTTL = 7 * 24 * time.Hour
+
+var (
+	//go:embed lua/create_favorite.lua
+	luaCreateFavorite string
+	//go:embed lua/delete_favorite.lua
+	luaDeleteFavorite string
+)
+
 type FavoriteCache struct {
 	cmd redis.Cmdable
 }
@@ -37,6 +50,28 @@ func (c *FavoriteCache) keys() struct {
 	// 用户维度的点赞记录set模板, 填充uid后使用
 	userFavoriteKey   string
 	userUnFavoriteKey string
+	// 被反作弊标记的点赞zset, member为"{biz}:{bizId}:{uid}", score为标记时间
+	flaggedKey string
+	// 被标记点赞的检测详情hash, field同flaggedKey的member
+	flaggedDetailKey string
+	// 用户隐私设置模板, 填充uid后使用
+	privacyKey string
+	// 已删除内容标记模板, 填充biz,bizId后使用, 到期自动清除
+	purgedKey string
+	// 点赞数变化的 pub/sub 频道
+	countChannel string
+	// 集合运算使用的临时key模板, 填充随机后缀后使用
+	tmpKey string
+	// 相关内容zset模板, 填充biz,bizId后使用, score为相似度
+	relatedKey string
+	// 内容归属hash, field为"{biz}:{bizId}", value为创作者ID
+	ownerKey string
+	// 创作者获赞数hash, field为"{biz}:{ownerId}", value为该创作者在biz下的内容点赞数之和
+	creatorCountKey string
+	// 活跃点赞用户HyperLogLog模板, 填充时间段编号后使用
+	activeKey string
+	// 上次成功同步到数据库的时间
+	syncedKey string
 } {
 	return struct {
 		countKey          string
@@ -44,12 +79,34 @@ func (c *FavoriteCache) keys() struct {
 		bizUserKey        string
 		userFavoriteKey   string
 		userUnFavoriteKey string
+		flaggedKey        string
+		flaggedDetailKey  string
+		privacyKey        string
+		purgedKey         string
+		countChannel      string
+		tmpKey            string
+		relatedKey        string
+		ownerKey          string
+		creatorCountKey   string
+		activeKey         string
+		syncedKey         string
 	}{
 		countKey:          "favorite:counts",          // 全局计数器
 		bizTypesKey:       "favorite:biz:types",       // 业务类型集合
 		bizUserKey:        "favorite:biz:%s:%d:users", // 记录内容被谁点赞
 		userFavoriteKey:   "favorite:user:%d",         // 记录用户点赞了什么
 		userUnFavoriteKey: "unfavorite:user:%d",       // 记录用户取消点赞了什么
+		flaggedKey:        "favorite:flagged",         // 记录可疑点赞
+		flaggedDetailKey:  "favorite:flagged:detail",  // 记录可疑点赞的检测详情
+		privacyKey:        "favorite:privacy:%d",      // 记录用户隐私设置
+		purgedKey:         "favorite:purged:%s:%d",    // 记录已删除的内容
+		countChannel:      "favorite:events:count",    // 推送点赞数变化
+		tmpKey:            "favorite:tmp:%s",          // 临时集合
+		relatedKey:        "favorite:related:%s:%d",   // 记录内容的相关内容
+		ownerKey:          "favorite:owners",          // 记录内容属于哪个创作者
+		creatorCountKey:   "favorite:creator:counts",  // 创作者获赞数
+		activeKey:         "favorite:active:%d",       // 记录时间段内的点赞用户
+		syncedKey:         "favorite:synced",          // 记录上次同步时间
 	}
 }
 
@@ -57,54 +114,163 @@ func (c *FavoriteCache) keys() struct {
 func (c *FavoriteCache) CreateFavorite(ctx context.Context, biz string, bizId, uid int64) error {
 	keys := c.keys()
 
-	pipe := c.cmd.TxPipeline()
-	pipe.SAdd(ctx, keys.bizTypesKey, biz)
+	now := time.Now().Unix()
+	return c.cmd.Eval(ctx, luaCreateFavorite, []string{
+		keys.bizTypesKey,
+		keys.countKey,
+		fmt.Sprintf(keys.userFavoriteKey, uid),
+		fmt.Sprintf(keys.userUnFavoriteKey, uid),
+		fmt.Sprintf(keys.bizUserKey, biz, bizId),
+		fmt.Sprintf(keys.activeKey, now/int64(activeBucket/time.Second)),
+		keys.ownerKey,
+		keys.creatorCountKey,
+	}, biz, fmt.Sprintf("%s:%d", biz, bizId), uid, now,
+		int64(userRecordTTL/time.Second), int64(activeRetention/time.Second), bizId, keys.countChannel).Err()
+}
 
-	field := fmt.Sprintf("%s:%d", biz, bizId)
-	pipe.HIncrBy(ctx, keys.countKey, field, 1)
+// DeleteFavorite 删除点赞记录及递减点赞数
+func (c *FavoriteCache) DeleteFavorite(ctx context.Context, biz string, bizId, uid int64) error {
+	keys := c.keys()
 
-	userKey := fmt.Sprintf(keys.userFavoriteKey, uid)
+	// 被标记的点赞从未计入公开计数, 只需清理标记及用户记录
+	flagged, err := c.cmd.ZRem(ctx, keys.flaggedKey, flaggedMember(biz, bizId, uid)).Result()
+	if err != nil {
+		return err
+	}
+	if flagged > 0 {
+		return c.removeFlagged(ctx, biz, bizId, uid)
+	}
+
+	return c.cmd.Eval(ctx, luaDeleteFavorite, []string{
+		keys.countKey,
+		fmt.Sprintf(keys.userUnFavoriteKey, uid),
+		fmt.Sprintf(keys.userFavoriteKey, uid),
+		fmt.Sprintf(keys.bizUserKey, biz, bizId),
+		keys.ownerKey,
+		keys.creatorCountKey,
+	}, biz, fmt.Sprintf("%s:%d", biz, bizId), uid, time.Now().Unix(), int64(userRecordTTL/time.Second),
+		bizId, keys.countChannel).Err()
+}
+
+// CreateShadowFavorite 创建被标记的点赞, 仅记录到用户维度, 不计入公开计数和点赞用户
+func (c *FavoriteCache) CreateShadowFavorite(ctx context.Context, fav domain.FlaggedFavorite) error {
+	keys := c.keys()
+
+	detail, err := json.Marshal(flaggedDetail{Score: fav.Score, Reasons: fav.Reasons})
+	if err != nil {
+		return err
+	}
+
+	pipe := c.cmd.TxPipeline()
+	pipe.SAdd(ctx, keys.bizTypesKey, fav.Biz)
+
+	userKey := fmt.Sprintf(keys.userFavoriteKey, fav.UserId)
 	pipe.ZAdd(ctx, userKey, redis.Z{
-		Score:  float64(time.Now().Unix()),
-		Member: fmt.Sprintf("%s:%d", biz, bizId),
+		Score:  float64(fav.FlaggedAt),
+		Member: fmt.Sprintf("%s:%d", fav.Biz, fav.BizId),
 	})
+	pipe.Expire(ctx, userKey, userRecordTTL)
 
-	// 记录内容被点赞
-	bizUserKey := fmt.Sprintf(keys.bizUserKey, biz, bizId)
-	pipe.SAdd(ctx, bizUserKey, uid)
-	pipe.Expire(ctx, userKey, 7*24*time.Hour)
+	member := flaggedMember(fav.Biz, fav.BizId, fav.UserId)
+	pipe.ZAdd(ctx, keys.flaggedKey, redis.Z{
+		Score:  float64(fav.FlaggedAt),
+		Member: member,
+	})
+	pipe.HSet(ctx, keys.flaggedDetailKey, member, detail)
 
-	_, err := pipe.Exec(ctx)
+	_, err = pipe.Exec(ctx)
 
 	return err
 }
 
-// DeleteFavorite 删除点赞记录及递减点赞数
-func (c *FavoriteCache) DeleteFavorite(ctx context.Context, biz string, bizId, uid int64) error {
+// ListFlaggedFavorites 按标记时间倒序获取被标记的点赞
+func (c *FavoriteCache) ListFlaggedFavorites(ctx context.Context, offset, limit int64) ([]domain.FlaggedFavorite, int64, error) {
 	keys := c.keys()
 
-	pipe := c.cmd.TxPipeline()
-	// 更新计数
-	field := fmt.Sprintf("%s:%d", biz, bizId)
-	pipe.HIncrBy(ctx, keys.countKey, field, -1)
+	total, err := c.cmd.ZCard(ctx, keys.flaggedKey).Result()
+	if err != nil {
+		return nil, 0, err
+	}
 
-	// 记录用户取消点赞
-	userKey := fmt.Sprintf(keys.userUnFavoriteKey, uid)
-	pipe.ZAdd(ctx, userKey, redis.Z{
-		Score:  float64(time.Now().Unix()),
-		Member: fmt.Sprintf("%s:%d", biz, bizId),
-	})
+	zs, err := c.cmd.ZRevRangeWithScores(ctx, keys.flaggedKey, offset, offset+limit-1).Result()
+	if err != nil {
+		return nil, 0, err
+	}
+	if len(zs) == 0 {
+		return nil, total, nil
+	}
 
-	// 删除点赞内容记录
-	bizUserKey := fmt.Sprintf(keys.bizUserKey, biz, bizId)
-	pipe.SRem(ctx, bizUserKey, uid)
+	members := make([]string, 0, len(zs))
+	for _, z := range zs {
+		members = append(members, z.Member.(string))
+	}
+	details, err := c.cmd.HMGet(ctx, keys.flaggedDetailKey, members...).Result()
+	if err != nil {
+		return nil, 0, err
+	}
+
+	res := make([]domain.FlaggedFavorite, 0, len(zs))
+	for i, z := range zs {
+		strs := strings.Split(members[i], ":")
+		if len(strs) != 3 {
+			continue
+		}
+		bizId, _ := strconv.ParseInt(strs[1], 10, 64)
+		uid, _ := strconv.ParseInt(strs[2], 10, 64)
 
-	pipe.Expire(ctx, userKey, 7*24*time.Hour)
+		var detail flaggedDetail
+		if v, ok := details[i].(string); ok {
+			_ = json.Unmarshal([]byte(v), &detail)
+		}
+
+		res = append(res, domain.FlaggedFavorite{
+			UserId:    uid,
+			Biz:       strs[0],
+			BizId:     bizId,
+			Score:     detail.Score,
+			Reasons:   detail.Reasons,
+			FlaggedAt: int64(z.Score),
+		})
+	}
+
+	return res, total, nil
+}
+
+// PurgeFlaggedFavorite 彻底清除一条被标记的点赞, 返回该点赞是否存在
+func (c *FavoriteCache) PurgeFlaggedFavorite(ctx context.Context, biz string, bizId, uid int64) (bool, error) {
+	keys := c.keys()
+
+	removed, err := c.cmd.ZRem(ctx, keys.flaggedKey, flaggedMember(biz, bizId, uid)).Result()
+	if err != nil {
+		return false, err
+	}
+	if removed == 0 {
+		return false, nil
+	}
+
+	return true, c.removeFlagged(ctx, biz, bizId, uid)
+}
+
+func (c *FavoriteCache) removeFlagged(ctx context.Context, biz string, bizId, uid int64) error {
+	keys := c.keys()
+
+	pipe := c.cmd.TxPipeline()
+	pipe.HDel(ctx, keys.flaggedDetailKey, flaggedMember(biz, bizId, uid))
+	pipe.ZRem(ctx, fmt.Sprintf(keys.userFavoriteKey, uid), fmt.Sprintf("%s:%d", biz, bizId))
 	_, err := pipe.Exec(ctx)
 
 	return err
 }
 
+type flaggedDetail struct {
+	Score   float64  `json:"score"`
+	Reasons []string `json:"reasons"`
+}
+
+func flaggedMember(biz string, bizId, uid int64) string {
+	return fmt.Sprintf("%s:%d:%d", biz, bizId, uid)
+}
+
 // FavoriteCount 获取单个内容的点赞总数
 func (c *FavoriteCache) FavoriteCount(ctx context.Context, biz string, bizId int64) (int64, error) {
 	keys := c.keys()
@@ -112,11 +278,14 @@ func (c *FavoriteCache) FavoriteCount(ctx context.Context, biz string, bizId int
 	field := fmt.Sprintf("%s:%d", biz, bizId)
 	res, err := c.cmd.HGet(ctx, keys.countKey, field).Int64()
 	if errors.Is(err, redis.Nil) {
+		// 点赞数不会过期, 不存在说明没有点赞
+		metrics.CacheEmpty("FavoriteCount")
 		return 0, nil
 	}
 	if err != nil {
 		return 0, err
 	}
+	metrics.CacheResult("FavoriteCount", true)
 
 	return res, nil
 }
@@ -130,8 +299,14 @@ func (c *FavoriteCache) BizFavoriteUser(ctx context.Context, biz string, bizId i
 	if err != nil {
 		return nil, err
 	}
+	// 点赞用户集合不会过期, 为空说明没有点赞
+	if len(res) == 0 {
+		metrics.CacheEmpty("BizFavoriteUser")
+	} else {
+		metrics.CacheResult("BizFavoriteUser", true)
+	}
 
-	users := make([]int64, len(res))
+	users := make([]int64, 0, len(res))
 	for _, v := range res {
 		uid, _ := strconv.ParseInt(v, 10, 64)
 		users = append(users, uid)
@@ -145,15 +320,14 @@ func (c *FavoriteCache) UserFavoriteCount(ctx context.Context, uid int64) (int64
 	keys := c.keys()
 
 	userKey := fmt.Sprintf(keys.userFavoriteKey, uid)
-	res, err := c.cmd.SCard(ctx, userKey).Uint64()
+	res, err := c.cmd.ZCard(ctx, userKey).Result()
 	if err != nil {
-		if errors.Is(err, redis.Nil) {
-			return 0, nil
-		}
 		return 0, err
 	}
+	// 用户点赞记录会过期, 不存在时无法区分没有点赞与已过期
+	metrics.CacheResult("UserFavoriteCount", res > 0)
 
-	return int64(res), nil
+	return res, nil
 }
 
 // UserFavoriteElements 用户点赞的内容集合
@@ -161,10 +335,11 @@ func (c *FavoriteCache) UserFavoriteElements(ctx context.Context, uid int64) ([]
 	keys := c.keys()
 
 	userKey := fmt.Sprintf(keys.userFavoriteKey, uid)
-	res, err := c.cmd.SMembers(ctx, userKey).Result()
+	res, err := c.cmd.ZRevRange(ctx, userKey, 0, -1).Result()
 	if err != nil {
 		return nil, err
 	}
+	metrics.CacheResult("UserFavoriteElements", len(res) > 0)
 
 	return res, nil
 }
@@ -178,7 +353,7 @@ func (c *FavoriteCache) UserFavoritedCount(ctx context.Context, biz string, bizI
 		bizUserKey := fmt.Sprintf(keys.bizUserKey, biz, v)
 		res, err := c.cmd.SCard(ctx, bizUserKey).Result()
 		if err != nil {
-			return 0, nil
+			return 0, err
 		}
 		count += res
 	}
@@ -188,12 +363,66 @@ func (c *FavoriteCache) UserFavoritedCount(ctx context.Context, biz string, bizI
 
 // IsUserFavorite 用户是否点赞了某个内容
 func (c *FavoriteCache) IsUserFavorite(ctx context.Context, biz string, uid, bizId int64) (bool, error) {
+	res, err := c.BatchIsUserFavorite(ctx, []domain.UserFavorite{{UserId: uid, Biz: biz, BizId: bizId}})
+	if err != nil {
+		return false, err
+	}
+	// 点赞用户集合不会过期, 未命中说明没有点赞
+	if res[0] {
+		metrics.CacheResult("IsUserFavorite", true)
+	} else {
+		metrics.CacheEmpty("IsUserFavorite")
+	}
+
+	return res[0], nil
+}
+
+// BatchIsUserFavorite 批量判断用户是否点赞了内容
+// 内容的点赞用户集合不会过期, 被标记的点赞只存在于用户点赞记录中, 两者任一命中即视为已点赞
+func (c *FavoriteCache) BatchIsUserFavorite(ctx context.Context, favs []domain.UserFavorite) ([]bool, error) {
 	keys := c.keys()
 
-	userKey := fmt.Sprintf(keys.userFavoriteKey, uid)
-	res, err := c.cmd.SIsMember(ctx, userKey, fmt.Sprintf("%s:%d", biz, bizId)).Result()
+	pipe := c.cmd.Pipeline()
+	inBiz := make([]*redis.BoolCmd, len(favs))
+	inUser := make([]*redis.FloatCmd, len(favs))
+	for i, f := range favs {
+		inBiz[i] = pipe.SIsMember(ctx, fmt.Sprintf(keys.bizUserKey, f.Biz, f.BizId), f.UserId)
+		inUser[i] = pipe.ZScore(ctx, fmt.Sprintf(keys.userFavoriteKey, f.UserId), fmt.Sprintf("%s:%d", f.Biz, f.BizId))
+	}
+	if _, err := pipe.Exec(ctx); err != nil && !errors.Is(err, redis.Nil) {
+		return nil, err
+	}
+
+	res := make([]bool, len(favs))
+	for i := range favs {
+		res[i] = inBiz[i].Val() || inUser[i].Err() == nil
+	}
+
+	return res, nil
+}
+
+// BatchFavoriteCount 批量获取内容的点赞数
+func (c *FavoriteCache) BatchFavoriteCount(ctx context.Context, contents []domain.FavoriteCount) ([]int64, error) {
+	keys := c.keys()
+
+	fields := make([]string, 0, len(contents))
+	for _, ct := range contents {
+		fields = append(fields, fmt.Sprintf("%s:%d", ct.Biz, ct.BizId))
+	}
+	vals, err := c.cmd.HMGet(ctx, keys.countKey, fields...).Result()
 	if err != nil {
-		return false, err
+		return nil, err
+	}
+
+	res := make([]int64, len(contents))
+	for i, v := range vals {
+		str, ok := v.(string)
+		if !ok {
+			metrics.CacheEmpty("BatchFavoriteCount")
+			continue
+		}
+		metrics.CacheResult("BatchFavoriteCount", true)
+		res[i], _ = strconv.ParseInt(str, 10, 64)
 	}
 
 	return res, nil
@@ -254,21 +483,33 @@ func (c *FavoriteCache) GetUserRecentFavorites(ctx context.Context, uid int64, l
 	return c.cmd.ZRevRange(ctx, userKey, 0, limit-1).Result()
 }
 
-// GetUserUnFavorites 获取用户的取消点赞记录
-func (c *FavoriteCache) GetUserUnFavorites(ctx context.Context, uid int64) (map[string]int64, error) {
+// GetUserUnFavorites 按取消时间倒序分页获取用户最近的取消点赞记录
+func (c *FavoriteCache) GetUserUnFavorites(ctx context.Context, uid int64, offset, limit int64) ([]domain.UnFavorite, error) {
 	keys := c.keys()
 	userKey := fmt.Sprintf(keys.userUnFavoriteKey, uid)
 
-	result, err := c.cmd.SMembers(ctx, userKey).Result()
-	if err != nil {
+	pipe := c.cmd.Pipeline()
+	exists := pipe.Exists(ctx, userKey)
+	zs := pipe.ZRevRangeWithScores(ctx, userKey, offset, offset+limit-1)
+	if _, err := pipe.Exec(ctx); err != nil {
 		return nil, err
 	}
-
-	res := make(map[string]int64, len(result))
-	for _, v := range result {
-		strs := strings.Split(v, ":")
-		bizId, _ := strconv.ParseInt(strs[1], 10, 64)
-		res[strs[0]] = bizId
+	metrics.CacheResult("GetUserUnFavorites", exists.Val() > 0)
+	result := zs.Val()
+
+	res := make([]domain.UnFavorite, 0, len(result))
+	for _, z := range result {
+		member := z.Member.(string)
+		idx := strings.LastIndex(member, ":")
+		if idx <= 0 {
+			continue
+		}
+		bizId, _ := strconv.ParseInt(member[idx+1:], 10, 64)
+		res = append(res, domain.UnFavorite{
+			Biz:       member[:idx],
+			BizId:     bizId,
+			UnlikedAt: int64(z.Score),
+		})
 	}
 	return res, nil
 }
@@ -337,3 +578,78 @@ func (c *FavoriteCache) GetAllCount(ctx context.Context) (<-chan domain.Favorite
 
 	return out, nil
 }
+
+// GetPrivacy 批量获取用户隐私设置, 同时返回缓存未命中的用户
+func (c *FavoriteCache) GetPrivacy(ctx context.Context, uids []int64) (map[int64]uint8, []int64, error) {
+	keys := c.keys()
+
+	privacyKeys := make([]string, 0, len(uids))
+	for _, uid := range uids {
+		privacyKeys = append(privacyKeys, fmt.Sprintf(keys.privacyKey, uid))
+	}
+	vals, err := c.cmd.MGet(ctx, privacyKeys...).Result()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	res := make(map[int64]uint8, len(uids))
+	var missing []int64
+	for i, v := range vals {
+		str, ok := v.(string)
+		metrics.CacheResult("GetPrivacy", ok)
+		if !ok {
+			missing = append(missing, uids[i])
+			continue
+		}
+		visibility, _ := strconv.ParseUint(str, 10, 8)
+		res[uids[i]] = uint8(visibility)
+	}
+
+	return res, missing, nil
+}
+
+// SetPrivacy 批量缓存用户隐私设置
+func (c *FavoriteCache) SetPrivacy(ctx context.Context, privacies map[int64]uint8) error {
+	keys := c.keys()
+
+	pipe := c.cmd.Pipeline()
+	for uid, visibility := range privacies {
+		pipe.Set(ctx, fmt.Sprintf(keys.privacyKey, uid), visibility, 24*time.Hour)
+	}
+	_, err := pipe.Exec(ctx)
+
+	return err
+}
+
+// DelPrivacy 删除用户隐私设置缓存
+func (c *FavoriteCache) DelPrivacy(ctx context.Context, uid int64) error {
+	keys := c.keys()
+
+	return c.cmd.Del(ctx, fmt.Sprintf(keys.privacyKey, uid)).Err()
+}
+
+// BizTypes 获取所有业务类型
+func (c *FavoriteCache) BizTypes(ctx context.Context) ([]string, error) {
+	keys := c.keys()
+
+	return c.cmd.SMembers(ctx, keys.bizTypesKey).Result()
+}
+
+// ResetBizUsers 用给定的用户覆盖内容的点赞用户集合
+func (c *FavoriteCache) ResetBizUsers(ctx context.Context, biz string, bizId int64, uids []int64) error {
+	keys := c.keys()
+
+	bizUserKey := fmt.Sprintf(keys.bizUserKey, biz, bizId)
+	pipe := c.cmd.TxPipeline()
+	pipe.Del(ctx, bizUserKey)
+	if len(uids) > 0 {
+		members := make([]any, 0, len(uids))
+		for _, uid := range uids {
+			members = append(members, uid)
+		}
+		pipe.SAdd(ctx, bizUserKey, members...)
+	}
+	_, err := pipe.Exec(ctx)
+
+	return err
+}
@@ -0,0 +1,50 @@
+-- 业务类型集合
+local bizTypesKey = KEYS[1]
+-- 全局计数器
+local countKey = KEYS[2]
+-- 用户点赞记录
+local userKey = KEYS[3]
+-- 用户取消点赞记录
+local unFavoriteKey = KEYS[4]
+-- 内容的点赞用户集合
+local bizUserKey = KEYS[5]
+-- 活跃点赞用户
+local activeKey = KEYS[6]
+-- 内容归属
+local ownerKey = KEYS[7]
+-- 创作者获赞数
+local creatorCountKey = KEYS[8]
+
+local biz = ARGV[1]
+-- "{biz}:{bizId}"
+local field = ARGV[2]
+local uid = ARGV[3]
+local now = ARGV[4]
+local userTTL = ARGV[5]
+local activeTTL = ARGV[6]
+local bizId = ARGV[7]
+-- 点赞数变化的推送频道
+local channel = ARGV[8]
+
+redis.call('SADD', bizTypesKey, biz)
+
+-- 归属与计数在同一脚本内读写, 与登记归属互斥, 创作者获赞数不会重复或遗漏
+local count = redis.call('HINCRBY', countKey, field, 1)
+local owner = redis.call('HGET', ownerKey, field)
+if owner then
+    redis.call('HINCRBY', creatorCountKey, biz .. ':' .. owner, 1)
+end
+
+redis.call('ZADD', userKey, now, field)
+redis.call('EXPIRE', userKey, userTTL)
+-- 重新点赞后从取消点赞记录中移除
+redis.call('ZREM', unFavoriteKey, field)
+redis.call('SADD', bizUserKey, uid)
+
+redis.call('PFADD', activeKey, uid)
+redis.call('EXPIRE', activeKey, activeTTL)
+
+-- 在脚本内推送点赞数变化, 推送顺序与计数的变化顺序一致
+redis.call('PUBLISH', channel, '{"biz":' .. cjson.encode(biz) .. ',"biz_id":' .. bizId .. ',"count":' .. count .. '}')
+
+return count
@@ -0,0 +1,33 @@
+-- 全局计数器
+local countKey = KEYS[1]
+-- 内容的点赞用户集合
+local bizUserKey = KEYS[2]
+-- 内容归属
+local ownerKey = KEYS[3]
+-- 创作者获赞数
+local creatorCountKey = KEYS[4]
+
+local biz = ARGV[1]
+-- "{biz}:{bizId}"
+local field = ARGV[2]
+local bizId = ARGV[3]
+-- 点赞数变化的推送频道
+local channel = ARGV[4]
+
+local count = tonumber(redis.call('HGET', countKey, field) or '0')
+redis.call('HDEL', countKey, field)
+redis.call('DEL', bizUserKey)
+
+-- 从创作者获赞数中扣除内容剩余的点赞数
+local owner = redis.call('HGET', ownerKey, field)
+if owner then
+    redis.call('HDEL', ownerKey, field)
+    if count ~= 0 then
+        redis.call('HINCRBY', creatorCountKey, biz .. ':' .. owner, -count)
+    end
+end
+
+-- 在脚本内推送点赞数变化, 推送顺序与计数的变化顺序一致
+redis.call('PUBLISH', channel, '{"biz":' .. cjson.encode(biz) .. ',"biz_id":' .. bizId .. ',"count":0}')
+
+return count
@@ -0,0 +1,38 @@
+-- 全局计数器
+local countKey = KEYS[1]
+-- 用户取消点赞记录
+local unFavoriteKey = KEYS[2]
+-- 用户点赞记录
+local userKey = KEYS[3]
+-- 内容的点赞用户集合
+local bizUserKey = KEYS[4]
+-- 内容归属
+local ownerKey = KEYS[5]
+-- 创作者获赞数
+local creatorCountKey = KEYS[6]
+
+local biz = ARGV[1]
+-- "{biz}:{bizId}"
+local field = ARGV[2]
+local uid = ARGV[3]
+local now = ARGV[4]
+local userTTL = ARGV[5]
+local bizId = ARGV[6]
+-- 点赞数变化的推送频道
+local channel = ARGV[7]
+
+local count = redis.call('HINCRBY', countKey, field, -1)
+local owner = redis.call('HGET', ownerKey, field)
+if owner then
+    redis.call('HINCRBY', creatorCountKey, biz .. ':' .. owner, -1)
+end
+
+redis.call('ZADD', unFavoriteKey, now, field)
+redis.call('EXPIRE', unFavoriteKey, userTTL)
+redis.call('ZREM', userKey, field)
+redis.call('SREM', bizUserKey, uid)
+
+-- 在脚本内推送点赞数变化, 推送顺序与计数的变化顺序一致
+redis.call('PUBLISH', channel, '{"biz":' .. cjson.encode(biz) .. ',"biz_id":' .. bizId .. ',"count":' .. count .. '}')
+
+return count
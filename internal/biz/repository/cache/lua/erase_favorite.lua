@@ -0,0 +1,45 @@
+-- 内容的点赞用户集合
+local bizUserKey = KEYS[1]
+-- 全局计数器
+local countKey = KEYS[2]
+-- 用户点赞记录
+local userKey = KEYS[3]
+-- 可疑点赞集合
+local flaggedKey = KEYS[4]
+-- 可疑点赞详情
+local flaggedDetailKey = KEYS[5]
+-- 内容归属
+local ownerKey = KEYS[6]
+-- 创作者获赞数
+local creatorCountKey = KEYS[7]
+
+local uid = ARGV[1]
+-- "{biz}:{bizId}"
+local field = ARGV[2]
+-- "{biz}:{bizId}:{uid}"
+local flaggedMember = ARGV[3]
+local biz = ARGV[4]
+local bizId = ARGV[5]
+-- 点赞数变化的推送频道
+local channel = ARGV[6]
+
+-- 只有确实在点赞用户集合中才递减计数, 保证重复执行时的幂等
+-- 返回递减后的点赞数, 未递减时返回 -1
+local count = -1
+local removed = redis.call('SREM', bizUserKey, uid)
+if removed == 1 then
+    count = redis.call('HINCRBY', countKey, field, -1)
+    local owner = redis.call('HGET', ownerKey, field)
+    if owner then
+        redis.call('HINCRBY', creatorCountKey, biz .. ':' .. owner, -1)
+    end
+
+    -- 在脚本内推送点赞数变化, 推送顺序与计数的变化顺序一致
+    redis.call('PUBLISH', channel, '{"biz":' .. cjson.encode(biz) .. ',"biz_id":' .. bizId .. ',"count":' .. count .. '}')
+end
+
+redis.call('ZREM', userKey, field)
+redis.call('ZREM', flaggedKey, flaggedMember)
+redis.call('HDEL', flaggedDetailKey, flaggedMember)
+
+return count
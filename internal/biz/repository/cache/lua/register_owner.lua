@@ -0,0 +1,28 @@
+-- 内容归属
+local ownerKey = KEYS[1]
+-- 全局计数器
+local countKey = KEYS[2]
+-- 创作者获赞数
+local creatorCountKey = KEYS[3]
+
+-- "{biz}:{bizId}"
+local field = ARGV[1]
+local biz = ARGV[2]
+local owner = ARGV[3]
+
+local old = redis.call('HGET', ownerKey, field)
+if old == owner then
+    return 0
+end
+redis.call('HSET', ownerKey, field, owner)
+
+-- 内容已有的点赞数计入新创作者, 归属变更时从原创作者扣除
+local count = tonumber(redis.call('HGET', countKey, field) or '0')
+if count ~= 0 then
+    if old then
+        redis.call('HINCRBY', creatorCountKey, biz .. ':' .. old, -count)
+    end
+    redis.call('HINCRBY', creatorCountKey, biz .. ':' .. owner, count)
+end
+
+return 1
@@ -0,0 +1,77 @@
+package cache
+
+import (
+	"context"
+	_ "embed"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/crazyfrankie/favorite/internal/biz/metrics"
+)
+
+//go:embed lua/register_owner.lua
+var luaRegisterOwner string
+
+// RegisterContentOwner 记录内容归属, 并将内容已有的点赞数计入创作者, 可重复执行
+func (c *FavoriteCache) RegisterContentOwner(ctx context.Context, biz string, bizId, ownerId int64) error {
+	keys := c.keys()
+
+	return c.cmd.Eval(ctx, luaRegisterOwner, []string{
+		keys.ownerKey,
+		keys.countKey,
+		keys.creatorCountKey,
+	}, fmt.Sprintf("%s:%d", biz, bizId), biz, ownerId).Err()
+}
+
+// ContentOwner 获取内容的创作者, 未登记归属时返回 0
+func (c *FavoriteCache) ContentOwner(ctx context.Context, biz string, bizId int64) (int64, error) {
+	owners, err := c.contentOwners(ctx, []string{fmt.Sprintf("%s:%d", biz, bizId)})
+	if err != nil {
+		return 0, err
+	}
+
+	return owners[0], nil
+}
+
+// CreatorReceivedCount 获取创作者在某个业务下收到的点赞总数, 计数不存在时返回 false
+func (c *FavoriteCache) CreatorReceivedCount(ctx context.Context, ownerId int64, biz string) (int64, bool, error) {
+	keys := c.keys()
+
+	res, err := c.cmd.HGet(ctx, keys.creatorCountKey, creatorField(biz, ownerId)).Int64()
+	if errors.Is(err, redis.Nil) {
+		metrics.CacheResult("CreatorReceivedCount", false)
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, err
+	}
+	metrics.CacheResult("CreatorReceivedCount", true)
+
+	return res, true, nil
+}
+
+// contentOwners 批量获取内容的创作者, members 格式为 "{biz}:{bizId}", 未登记归属的内容返回 0
+func (c *FavoriteCache) contentOwners(ctx context.Context, members []string) ([]int64, error) {
+	keys := c.keys()
+
+	vals, err := c.cmd.HMGet(ctx, keys.ownerKey, members...).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]int64, len(members))
+	for i, v := range vals {
+		if s, ok := v.(string); ok {
+			res[i], _ = strconv.ParseInt(s, 10, 64)
+		}
+	}
+
+	return res, nil
+}
+
+func creatorField(biz string, ownerId int64) string {
+	return fmt.Sprintf("%s:%d", biz, ownerId)
+}
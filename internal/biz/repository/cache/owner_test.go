@@ -0,0 +1,55 @@
+package cache
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegisterContentOwner(t *testing.T) {
+	ctx := context.Background()
+	c, mr := newTestCache(t)
+	keys := c.keys()
+
+	// 登记前已有的点赞数计入创作者
+	require.NoError(t, c.CreateFavorite(ctx, "post", 1, 10))
+	require.NoError(t, c.CreateFavorite(ctx, "post", 1, 11))
+	require.NoError(t, c.RegisterContentOwner(ctx, "post", 1, 100))
+	assert.Equal(t, "2", mr.HGet(keys.creatorCountKey, "post:100"))
+
+	// 重复登记不会重复计数
+	require.NoError(t, c.RegisterContentOwner(ctx, "post", 1, 100))
+	assert.Equal(t, "2", mr.HGet(keys.creatorCountKey, "post:100"))
+
+	// 归属变更时从原创作者扣除
+	require.NoError(t, c.RegisterContentOwner(ctx, "post", 1, 200))
+	assert.Equal(t, "0", mr.HGet(keys.creatorCountKey, "post:100"))
+	assert.Equal(t, "2", mr.HGet(keys.creatorCountKey, "post:200"))
+
+	owner, err := c.ContentOwner(ctx, "post", 1)
+	require.NoError(t, err)
+	assert.Equal(t, int64(200), owner)
+
+	// 登记后的点赞及取消同步到创作者获赞数
+	require.NoError(t, c.CreateFavorite(ctx, "post", 1, 12))
+	require.NoError(t, c.DeleteFavorite(ctx, "post", 1, 10))
+	cnt, ok, err := c.CreatorReceivedCount(ctx, 200, "post")
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, int64(2), cnt)
+}
+
+func TestContentOwnerUnregistered(t *testing.T) {
+	ctx := context.Background()
+	c, _ := newTestCache(t)
+
+	owner, err := c.ContentOwner(ctx, "post", 1)
+	require.NoError(t, err)
+	assert.Zero(t, owner)
+
+	_, ok, err := c.CreatorReceivedCount(ctx, 100, "post")
+	require.NoError(t, err)
+	assert.False(t, ok)
+}
@@ -0,0 +1,78 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/crazyfrankie/favorite/internal/biz/domain"
+	"github.com/crazyfrankie/favorite/internal/biz/metrics"
+)
+
+// relatedEmptyMember 没有相关内容时写入的占位成员, 相似度均大于 0, 占位成员总是排在最后
+const relatedEmptyMember = "0"
+
+// SetRelatedContent 覆盖写入一批内容的相关内容, 没有相关内容的写入占位成员;
+// ttl 应大于计算周期, 计算中断时旧结果自然过期
+func (c *FavoriteCache) SetRelatedContent(ctx context.Context, biz string, related map[int64][]domain.RelatedItem, ttl time.Duration) error {
+	if len(related) == 0 {
+		return nil
+	}
+	keys := c.keys()
+
+	pipe := c.cmd.Pipeline()
+	for bizId, items := range related {
+		key := fmt.Sprintf(keys.relatedKey, biz, bizId)
+		pipe.Del(ctx, key)
+		if len(items) == 0 {
+			pipe.ZAdd(ctx, key, redis.Z{Score: 0, Member: relatedEmptyMember})
+			pipe.Expire(ctx, key, ttl)
+			continue
+		}
+
+		zs := make([]redis.Z, 0, len(items))
+		for _, item := range items {
+			zs = append(zs, redis.Z{Score: item.Score, Member: item.BizId})
+		}
+		pipe.ZAdd(ctx, key, zs...)
+		pipe.Expire(ctx, key, ttl)
+	}
+	_, err := pipe.Exec(ctx)
+
+	return err
+}
+
+// RelatedContent 获取相似度最高的 k 个相关内容, 缓存不存在时返回 false
+func (c *FavoriteCache) RelatedContent(ctx context.Context, biz string, bizId int64, k int64) ([]domain.RelatedItem, bool, error) {
+	keys := c.keys()
+
+	key := fmt.Sprintf(keys.relatedKey, biz, bizId)
+	pipe := c.cmd.Pipeline()
+	exists := pipe.Exists(ctx, key)
+	zs := pipe.ZRevRangeWithScores(ctx, key, 0, k-1)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return nil, false, err
+	}
+	metrics.CacheResult("RelatedContent", exists.Val() > 0)
+	if exists.Val() == 0 {
+		return nil, false, nil
+	}
+
+	res := make([]domain.RelatedItem, 0, len(zs.Val()))
+	for _, z := range zs.Val() {
+		member, _ := z.Member.(string)
+		if member == relatedEmptyMember {
+			continue
+		}
+		id, err := strconv.ParseInt(member, 10, 64)
+		if err != nil {
+			continue
+		}
+		res = append(res, domain.RelatedItem{BizId: id, Score: z.Score})
+	}
+
+	return res, true, nil
+}
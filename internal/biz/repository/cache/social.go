@@ -0,0 +1,54 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"strconv"
+	"time"
+
+	"github.com/crazyfrankie/favorite/internal/biz/metrics"
+)
+
+// ErrLikersNotCached 内容的点赞用户集合不在缓存中, 可能尚无点赞或已被淘汰
+var ErrLikersNotCached = errors.New("biz likers not cached")
+
+// FriendLikers 求好友列表与内容点赞用户集合的交集, 点赞用户集合不存在时返回 ErrLikersNotCached
+func (c *FavoriteCache) FriendLikers(ctx context.Context, biz string, bizId int64, friends []int64) ([]int64, error) {
+	if len(friends) == 0 {
+		return nil, nil
+	}
+	keys := c.keys()
+
+	members := make([]any, 0, len(friends))
+	for _, uid := range friends {
+		members = append(members, uid)
+	}
+
+	// 好友列表写入临时集合后与点赞用户集合求交集, 临时集合设置过期时间避免异常退出时残留
+	tmpKey := fmt.Sprintf(keys.tmpKey, strconv.FormatUint(rand.Uint64(), 36))
+	bizUserKey := fmt.Sprintf(keys.bizUserKey, biz, bizId)
+	pipe := c.cmd.Pipeline()
+	exists := pipe.Exists(ctx, bizUserKey)
+	pipe.SAdd(ctx, tmpKey, members...)
+	pipe.Expire(ctx, tmpKey, time.Minute)
+	inter := pipe.SInter(ctx, tmpKey, bizUserKey)
+	pipe.Del(ctx, tmpKey)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return nil, err
+	}
+	if exists.Val() == 0 {
+		metrics.CacheResult("FriendLikers", false)
+		return nil, ErrLikersNotCached
+	}
+	metrics.CacheResult("FriendLikers", true)
+
+	res := make([]int64, 0, len(inter.Val()))
+	for _, v := range inter.Val() {
+		uid, _ := strconv.ParseInt(v, 10, 64)
+		res = append(res, uid)
+	}
+
+	return res, nil
+}
@@ -0,0 +1,25 @@
+package cache
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFriendLikers(t *testing.T) {
+	ctx := context.Background()
+	c, _ := newTestCache(t)
+
+	require.NoError(t, c.CreateFavorite(ctx, "post", 1, 10))
+	require.NoError(t, c.CreateFavorite(ctx, "post", 1, 11))
+
+	res, err := c.FriendLikers(ctx, "post", 1, []int64{10, 12})
+	require.NoError(t, err)
+	assert.Equal(t, []int64{10}, res)
+
+	// 点赞用户集合不存在时交由调用方查询数据库, 而不是返回空结果
+	_, err = c.FriendLikers(ctx, "post", 2, []int64{10, 12})
+	assert.ErrorIs(t, err, ErrLikersNotCached)
+}
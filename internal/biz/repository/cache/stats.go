@@ -0,0 +1,97 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	// 活跃点赞用户按时间段记录, 查询时合并最近若干个时间段
+	activeBucket    = 5 * time.Minute
+	activeRetention = 2 * time.Hour
+)
+
+// ActiveLikers 获取最近 window 内点赞过的去重用户数(近似值)
+func (c *FavoriteCache) ActiveLikers(ctx context.Context, window time.Duration) (int64, error) {
+	keys := c.keys()
+
+	window = min(window, activeRetention)
+	now := time.Now().Unix() / int64(activeBucket/time.Second)
+	n := int64(window / activeBucket)
+	activeKeys := make([]string, 0, n)
+	for i := int64(0); i < max(n, 1); i++ {
+		activeKeys = append(activeKeys, fmt.Sprintf(keys.activeKey, now-i))
+	}
+
+	return c.cmd.PFCount(ctx, activeKeys...).Result()
+}
+
+// SampleCounterDrift 随机抽取 n 个内容, 按业务统计点赞计数与点赞用户集合大小之差的绝对值之和
+func (c *FavoriteCache) SampleCounterDrift(ctx context.Context, n int) (map[string]int64, error) {
+	keys := c.keys()
+
+	fields, err := c.cmd.HRandFieldWithValues(ctx, keys.countKey, n).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	pipe := c.cmd.Pipeline()
+	cards := make([]*redis.IntCmd, len(fields))
+	for i, kv := range fields {
+		idx := strings.LastIndex(kv.Key, ":")
+		if idx < 0 {
+			continue
+		}
+		bizId, err := strconv.ParseInt(kv.Key[idx+1:], 10, 64)
+		if err != nil {
+			continue
+		}
+		cards[i] = pipe.SCard(ctx, fmt.Sprintf(keys.bizUserKey, kv.Key[:idx], bizId))
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		return nil, err
+	}
+
+	res := make(map[string]int64)
+	for i, kv := range fields {
+		if cards[i] == nil {
+			continue
+		}
+		count, _ := strconv.ParseInt(kv.Value, 10, 64)
+		drift := count - cards[i].Val()
+		if drift < 0 {
+			drift = -drift
+		}
+		res[kv.Key[:strings.LastIndex(kv.Key, ":")]] += drift
+	}
+
+	return res, nil
+}
+
+// MarkSynced 记录成功同步到数据库的时间
+func (c *FavoriteCache) MarkSynced(ctx context.Context, at time.Time) error {
+	keys := c.keys()
+
+	return c.cmd.Set(ctx, keys.syncedKey, at.Unix(), 0).Err()
+}
+
+// LastSynced 获取上次成功同步到数据库的时间, 从未同步时返回零值
+func (c *FavoriteCache) LastSynced(ctx context.Context) (time.Time, error) {
+	keys := c.keys()
+
+	res, err := c.cmd.Get(ctx, keys.syncedKey).Int64()
+	if errors.Is(err, redis.Nil) {
+		return time.Time{}, nil
+	}
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	return time.Unix(res, 0), nil
+}
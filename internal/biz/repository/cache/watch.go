@@ -0,0 +1,67 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/crazyfrankie/favorite/internal/biz/domain"
+)
+
+var ErrSubscribeUnsupported = errors.New("redis client does not support pub/sub")
+
+type countChange struct {
+	Biz   string `json:"biz"`
+	BizId int64  `json:"biz_id"`
+	Count int64  `json:"count"`
+}
+
+// publishCounts 在写入计数的事务中推送点赞数变化, 保证推送顺序与计数的变化顺序一致;
+// Lua 脚本中修改计数的在脚本内推送, 格式与 countChange 相同
+func (c *FavoriteCache) publishCounts(ctx context.Context, pipe redis.Pipeliner, counts ...domain.FavoriteCount) {
+	keys := c.keys()
+
+	for _, cnt := range counts {
+		payload, err := json.Marshal(countChange{Biz: cnt.Biz, BizId: cnt.BizId, Count: cnt.Count})
+		if err != nil {
+			continue
+		}
+		pipe.Publish(ctx, keys.countChannel, payload)
+	}
+}
+
+// WatchCounts 订阅所有内容的点赞数变化, 阻塞直到 ctx 被取消
+func (c *FavoriteCache) WatchCounts(ctx context.Context, fn func(domain.FavoriteCount)) error {
+	keys := c.keys()
+
+	sub, ok := c.cmd.(interface {
+		Subscribe(ctx context.Context, channels ...string) *redis.PubSub
+	})
+	if !ok {
+		return ErrSubscribeUnsupported
+	}
+
+	pubsub := sub.Subscribe(ctx, keys.countChannel)
+	defer pubsub.Close()
+
+	// 断线后 go-redis 会自动重新订阅
+	ch := pubsub.Channel()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case msg, ok := <-ch:
+			if !ok {
+				return nil
+			}
+
+			var change countChange
+			if err := json.Unmarshal([]byte(msg.Payload), &change); err != nil {
+				continue
+			}
+			fn(domain.FavoriteCount{Biz: change.Biz, BizId: change.BizId, Count: change.Count})
+		}
+	}
+}
@@ -0,0 +1,88 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/crazyfrankie/favorite/internal/biz/domain"
+)
+
+func TestWatchCountsOrder(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	c, mr := newTestCache(t)
+
+	changes := make(chan domain.FavoriteCount, 16)
+	done := make(chan error, 1)
+	go func() {
+		done <- c.WatchCounts(ctx, func(cnt domain.FavoriteCount) {
+			changes <- cnt
+		})
+	}()
+	require.Eventually(t, func() bool {
+		return mr.PubSubNumSub(c.keys().countChannel)[c.keys().countChannel] == 1
+	}, time.Second, 10*time.Millisecond)
+
+	require.NoError(t, c.CreateFavorite(ctx, "post", 1, 10))
+	require.NoError(t, c.CreateFavorite(ctx, "post", 1, 11))
+	require.NoError(t, c.DeleteFavorite(ctx, "post", 1, 10))
+	require.NoError(t, c.EraseFavorite(ctx, "post", 1, 11))
+	// 未点赞时擦除不改变计数, 不推送
+	require.NoError(t, c.EraseFavorite(ctx, "post", 1, 11))
+	require.NoError(t, c.SetFavoriteCounts(ctx, []domain.FavoriteCount{{Biz: "post", BizId: 1, Count: 5}}))
+	require.NoError(t, c.DeleteContent(ctx, "post", 1))
+
+	// 推送顺序与计数的变化顺序一致
+	want := []int64{1, 2, 1, 0, 5, 0}
+	for _, w := range want {
+		select {
+		case got := <-changes:
+			assert.Equal(t, domain.FavoriteCount{Biz: "post", BizId: 1, Count: w}, got)
+		case <-time.After(time.Second):
+			t.Fatalf("missing count change %d", w)
+		}
+	}
+	select {
+	case got := <-changes:
+		t.Fatalf("unexpected count change %+v", got)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	cancel()
+	require.NoError(t, <-done)
+}
+
+func TestWatchCountsLargeBizId(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	c, mr := newTestCache(t)
+
+	changes := make(chan domain.FavoriteCount, 1)
+	done := make(chan error, 1)
+	go func() {
+		done <- c.WatchCounts(ctx, func(cnt domain.FavoriteCount) {
+			changes <- cnt
+		})
+	}()
+	require.Eventually(t, func() bool {
+		return mr.PubSubNumSub(c.keys().countChannel)[c.keys().countChannel] == 1
+	}, time.Second, 10*time.Millisecond)
+
+	// 雪花 ID 超出 Lua 数值的精度, 脚本内须按原样拼接
+	const bizId = int64(1834567890123456789)
+	require.NoError(t, c.CreateFavorite(ctx, `p"ost`, bizId, 10))
+
+	select {
+	case got := <-changes:
+		assert.Equal(t, domain.FavoriteCount{Biz: `p"ost`, BizId: bizId, Count: 1}, got)
+	case <-time.After(time.Second):
+		t.Fatal("missing count change")
+	}
+
+	cancel()
+	require.NoError(t, <-done)
+}
@@ -0,0 +1,50 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/crazyfrankie/favorite/internal/biz/domain"
+)
+
+// CommonFavorites 获取两个用户都点赞了且未被删除的内容;
+// 缓存中的用户点赞记录会过期和被裁剪, 且包含被标记的点赞, 不能用来求交集, 因此直接查询数据库
+func (r *FavoriteRepo) CommonFavorites(ctx context.Context, uidA, uidB int64, biz string, limit int) ([]domain.CommonFavorite, error) {
+	// 多取一些, 弥补已删除内容被过滤掉的部分
+	favs, err := r.read.ListCommonFavorites(ctx, uidA, uidB, biz, limit*2)
+	if err != nil {
+		return nil, err
+	}
+	res := make([]domain.CommonFavorite, 0, len(favs))
+	for _, f := range favs {
+		res = append(res, domain.CommonFavorite{Biz: f.Biz, BizId: f.BizId, LikedAt: f.LikedAt})
+	}
+
+	members := make([]string, 0, len(res))
+	for _, f := range res {
+		members = append(members, fmt.Sprintf("%s:%d", f.Biz, f.BizId))
+	}
+	alive, err := r.cache.FilterPurged(ctx, members)
+	if err != nil {
+		return nil, err
+	}
+	if len(alive) < len(members) {
+		keep := make(map[string]struct{}, len(alive))
+		for _, m := range alive {
+			keep[m] = struct{}{}
+		}
+		filtered := res[:0]
+		for i, f := range res {
+			if _, ok := keep[members[i]]; ok {
+				filtered = append(filtered, f)
+			}
+		}
+		res = filtered
+	}
+
+	if len(res) > limit {
+		res = res[:limit]
+	}
+
+	return res, nil
+}
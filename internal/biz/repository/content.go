@@ -0,0 +1,54 @@
+package repository
+
+import (
+	"context"
+)
+
+// PurgeContent 内容删除后清理其全部点赞数据, 返回被清理的点赞用户数
+func (r *FavoriteRepo) PurgeContent(ctx context.Context, biz string, bizId int64) (int64, error) {
+	// 先打标记, 清理过程中及清理后的点赞与列表查询都会过滤该内容
+	if err := r.cache.MarkContentPurged(ctx, biz, bizId); err != nil {
+		return 0, err
+	}
+
+	// 点赞用户可能很多, 分批读取并从各自的点赞记录中移除后再移出集合, 中途失败时重试不会遗漏
+	var purged int64
+	batchSize := int64(500)
+	for {
+		uids, err := r.cache.BizUsersBatch(ctx, biz, bizId, batchSize)
+		if err != nil {
+			return purged, err
+		}
+		if len(uids) == 0 {
+			break
+		}
+		if err := r.cache.RemoveFromUsers(ctx, biz, bizId, uids); err != nil {
+			return purged, err
+		}
+		if err := r.cache.RemoveBizUsers(ctx, biz, bizId, uids); err != nil {
+			return purged, err
+		}
+		purged += int64(len(uids))
+	}
+
+	flagged, err := r.cache.PurgeFlaggedContent(ctx, biz, bizId)
+	if err != nil {
+		return purged, err
+	}
+	purged += flagged
+
+	if err := r.cache.DeleteContent(ctx, biz, bizId); err != nil {
+		return purged, err
+	}
+
+	if _, err := r.write.DeleteContent(ctx, biz, bizId, 1000); err != nil {
+		return purged, err
+	}
+
+	return purged, nil
+}
+
+// IsContentPurged 内容是否已被删除
+func (r *FavoriteRepo) IsContentPurged(ctx context.Context, biz string, bizId int64) (bool, error) {
+	return r.cache.IsContentPurged(ctx, biz, bizId)
+}
@@ -0,0 +1,113 @@
+package dao
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const actionLogTable = "favorite_action_log"
+
+// InitActionLogTable 创建按月分区的操作流水表, AutoMigrate 无法声明分区, 这里直接使用 DDL
+func InitActionLogTable(db *gorm.DB) error {
+	now := time.Now()
+	cur := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.Local)
+
+	ddl := fmt.Sprintf("CREATE TABLE IF NOT EXISTS `%s` ("+
+		"`id` BIGINT NOT NULL AUTO_INCREMENT,"+
+		"`user_id` BIGINT NOT NULL,"+
+		"`biz` VARCHAR(128) NOT NULL,"+
+		"`biz_id` BIGINT NOT NULL,"+
+		"`action_type` TINYINT UNSIGNED NOT NULL,"+
+		"`caller_id` BIGINT NOT NULL DEFAULT 0,"+
+		"`client_ip` VARCHAR(64) NOT NULL DEFAULT '',"+
+		"`trace_id` VARCHAR(32) NOT NULL DEFAULT '',"+
+		"`ctime` BIGINT NOT NULL,"+
+		"PRIMARY KEY (`id`, `ctime`),"+
+		"KEY `idx_uid_ctime` (`user_id`, `ctime`),"+
+		"KEY `idx_biz_ctime` (`biz`, `biz_id`, `ctime`)"+
+		") PARTITION BY RANGE (`ctime`) (%s, PARTITION pmax VALUES LESS THAN MAXVALUE)",
+		actionLogTable, partitionDef(cur))
+
+	return db.Exec(ddl).Error
+}
+
+// EnsureActionLogPartitions 保证从当前月起未来 months 个月的分区存在
+func (d *FavoriteWriteDao) EnsureActionLogPartitions(ctx context.Context, months int) error {
+	var names []string
+	err := d.db.WithContext(ctx).Raw("SELECT PARTITION_NAME FROM information_schema.PARTITIONS "+
+		"WHERE TABLE_SCHEMA = DATABASE() AND TABLE_NAME = ?", actionLogTable).Scan(&names).Error
+	if err != nil {
+		return err
+	}
+	exists := make(map[string]bool, len(names))
+	for _, n := range names {
+		exists[n] = true
+	}
+
+	now := time.Now()
+	cur := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.Local)
+	var defs []string
+	for i := 0; i <= months; i++ {
+		month := cur.AddDate(0, i, 0)
+		if !exists[partitionName(month)] {
+			defs = append(defs, partitionDef(month))
+		}
+	}
+	if len(defs) == 0 {
+		return nil
+	}
+
+	// 从 pmax 中拆出新的分区
+	return d.db.WithContext(ctx).Exec(fmt.Sprintf(
+		"ALTER TABLE `%s` REORGANIZE PARTITION pmax INTO (%s, PARTITION pmax VALUES LESS THAN MAXVALUE)",
+		actionLogTable, strings.Join(defs, ", "))).Error
+}
+
+// CreateActionLog 追加一条操作流水
+func (d *FavoriteWriteDao) CreateActionLog(ctx context.Context, log FavoriteActionLog) error {
+	return d.db.WithContext(ctx).Create(&log).Error
+}
+
+// ListActionLogs 按时间范围查询用户或内容的操作流水
+func (d *FavoriteReadDao) ListActionLogs(ctx context.Context, uid int64, biz string, bizId, start, end int64, offset, limit int) ([]FavoriteActionLog, error) {
+	query := d.db.WithContext(ctx).Where("ctime >= ? AND ctime < ?", start, end)
+	if uid > 0 {
+		query = query.Where("user_id = ?", uid)
+	}
+	if biz != "" {
+		query = query.Where("biz = ? AND biz_id = ?", biz, bizId)
+	}
+
+	var logs []FavoriteActionLog
+	err := query.Order("ctime DESC, id DESC").Offset(offset).Limit(limit).Find(&logs).Error
+
+	return logs, err
+}
+
+// LastActionLog 获取用户对某个内容在 at 时刻之前的最后一次操作
+func (d *FavoriteReadDao) LastActionLog(ctx context.Context, uid int64, biz string, bizId, at int64) (FavoriteActionLog, error) {
+	var log FavoriteActionLog
+	err := d.db.WithContext(ctx).
+		Where("user_id = ? AND biz = ? AND biz_id = ? AND ctime <= ?", uid, biz, bizId, at).
+		Order("ctime DESC, id DESC").
+		First(&log).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return FavoriteActionLog{}, ErrRecordNotFound
+	}
+
+	return log, err
+}
+
+func partitionName(month time.Time) string {
+	return "p" + month.Format("200601")
+}
+
+func partitionDef(month time.Time) string {
+	return fmt.Sprintf("PARTITION %s VALUES LESS THAN (%d)",
+		partitionName(month), month.AddDate(0, 1, 0).UnixMilli())
+}
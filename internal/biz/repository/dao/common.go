@@ -0,0 +1,29 @@
+package dao
+
+import (
+	"context"
+
+	"github.com/crazyfrankie/favorite/pkg/constants"
+)
+
+type CommonFavorite struct {
+	Biz     string
+	BizId   int64
+	LikedAt int64
+}
+
+// ListCommonFavorites 获取两个用户都点赞了的内容, 按较晚的点赞时间倒序, biz 为空时不限业务
+func (d *FavoriteReadDao) ListCommonFavorites(ctx context.Context, uidA, uidB int64, biz string, limit int) ([]CommonFavorite, error) {
+	query := d.db.WithContext(ctx).Table("user_favorite AS a").
+		Select("a.biz, a.biz_id, GREATEST(a.utime, b.utime) AS liked_at").
+		Joins("JOIN user_favorite AS b ON a.biz = b.biz AND a.biz_id = b.biz_id").
+		Where("a.user_id = ? AND b.user_id = ? AND a.status = ? AND b.status = ?", uidA, uidB, constants.FavoriteStatusLiked, constants.FavoriteStatusLiked)
+	if biz != "" {
+		query = query.Where("a.biz = ?", biz)
+	}
+
+	var res []CommonFavorite
+	err := query.Order("liked_at DESC").Limit(limit).Scan(&res).Error
+
+	return res, err
+}
@@ -0,0 +1,34 @@
+package dao
+
+import (
+	"context"
+)
+
+// DeleteContent 删除内容的点赞计数、归属及全部点赞记录, 点赞记录分批删除避免长事务
+func (d *FavoriteWriteDao) DeleteContent(ctx context.Context, biz string, bizId int64, batchSize int) (int64, error) {
+	if err := d.db.WithContext(ctx).
+		Where("biz = ? AND biz_id = ?", biz, bizId).
+		Delete(&FavoriteCount{}).Error; err != nil {
+		return 0, err
+	}
+	if err := d.db.WithContext(ctx).
+		Where("biz = ? AND biz_id = ?", biz, bizId).
+		Delete(&ContentOwner{}).Error; err != nil {
+		return 0, err
+	}
+
+	var deleted int64
+	for {
+		res := d.db.WithContext(ctx).
+			Where("biz = ? AND biz_id = ?", biz, bizId).
+			Limit(batchSize).
+			Delete(&UserFavorite{})
+		if res.Error != nil {
+			return deleted, res.Error
+		}
+		deleted += res.RowsAffected
+		if res.RowsAffected < int64(batchSize) {
+			return deleted, nil
+		}
+	}
+}
@@ -0,0 +1,115 @@
+package dao
+
+import (
+	"context"
+	"errors"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/crazyfrankie/favorite/pkg/constants"
+)
+
+// CreateEraseTask 创建擦除任务, 已存在时保持原有进度
+func (d *FavoriteWriteDao) CreateEraseTask(ctx context.Context, uid int64) error {
+	return d.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&EraseTask{UserId: uid}).Error
+}
+
+// ClaimEraseTask 领取擦除任务, 只有等待中、失败或租约已过期的任务可被领取, 返回是否领取成功
+func (d *FavoriteWriteDao) ClaimEraseTask(ctx context.Context, uid int64, owner string, now, leaseUntil int64) (bool, error) {
+	res := d.db.WithContext(ctx).Model(&EraseTask{}).
+		Where("user_id = ? AND (status IN ? OR (status = ? AND lease_until < ?))", uid,
+			[]uint8{constants.EraseStatusPending, constants.EraseStatusFailed}, constants.EraseStatusRunning, now).
+		Updates(map[string]any{
+			"status":      constants.EraseStatusRunning,
+			"owner":       owner,
+			"lease_until": leaseUntil,
+		})
+
+	return res.RowsAffected > 0, res.Error
+}
+
+// UpdateClaimedEraseTask 更新 owner 领取的擦除任务, 任务已被其他实例重新领取时返回 false
+func (d *FavoriteWriteDao) UpdateClaimedEraseTask(ctx context.Context, uid int64, owner string, updates map[string]any) (bool, error) {
+	res := d.db.WithContext(ctx).Model(&EraseTask{}).
+		Where("user_id = ? AND owner = ?", uid, owner).Updates(updates)
+	if res.Error != nil || res.RowsAffected > 0 {
+		return res.RowsAffected > 0, res.Error
+	}
+
+	// 取值未变化时 MySQL 返回的影响行数为 0, 需再确认任务是否仍归 owner 所有
+	var count int64
+	err := d.db.WithContext(ctx).Model(&EraseTask{}).
+		Where("user_id = ? AND owner = ?", uid, owner).Count(&count).Error
+
+	return count > 0, err
+}
+
+// ResetEraseTask 重新发起已完成的擦除任务
+func (d *FavoriteWriteDao) ResetEraseTask(ctx context.Context, uid int64, status uint8) error {
+	return d.db.WithContext(ctx).Model(&EraseTask{}).
+		Where("user_id = ?", uid).
+		Updates(map[string]any{"status": status, "cursor": 0, "processed": 0, "error": "", "owner": "", "lease_until": 0, "finished_at": 0}).Error
+}
+
+// DeleteUserFavorites 按主键删除用户点赞记录
+func (d *FavoriteWriteDao) DeleteUserFavorites(ctx context.Context, ids []int64) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	return d.db.WithContext(ctx).Where("id IN ?", ids).Delete(&UserFavorite{}).Error
+}
+
+// DeleteUserActionLogs 删除用户的操作流水, 单次最多删除 limit 条, 返回删除的条数
+func (d *FavoriteWriteDao) DeleteUserActionLogs(ctx context.Context, uid int64, limit int) (int64, error) {
+	res := d.db.WithContext(ctx).Exec("DELETE FROM "+actionLogTable+" WHERE user_id = ? LIMIT ?", uid, limit)
+
+	return res.RowsAffected, res.Error
+}
+
+// DeletePrivacy 删除用户的隐私设置
+func (d *FavoriteWriteDao) DeletePrivacy(ctx context.Context, uid int64) error {
+	return d.db.WithContext(ctx).Where("user_id = ?", uid).Delete(&UserPrivacy{}).Error
+}
+
+// GetEraseTask 获取用户的擦除任务
+func (d *FavoriteReadDao) GetEraseTask(ctx context.Context, uid int64) (EraseTask, error) {
+	var task EraseTask
+	err := d.db.WithContext(ctx).Where("user_id = ?", uid).First(&task).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return EraseTask{}, ErrRecordNotFound
+	}
+
+	return task, err
+}
+
+// ListClaimableEraseTasks 获取可被领取的擦除任务, 即等待中、失败或租约已过期的任务
+func (d *FavoriteReadDao) ListClaimableEraseTasks(ctx context.Context, now int64, limit int) ([]EraseTask, error) {
+	var tasks []EraseTask
+	err := d.db.WithContext(ctx).
+		Where("status IN ? OR (status = ? AND lease_until < ?)",
+			[]uint8{constants.EraseStatusPending, constants.EraseStatusFailed}, constants.EraseStatusRunning, now).
+		Order("id").Limit(limit).Find(&tasks).Error
+
+	return tasks, err
+}
+
+// ListUserFavorites 按主键游标分页获取用户的点赞记录
+func (d *FavoriteReadDao) ListUserFavorites(ctx context.Context, uid, cursor int64, limit int) ([]UserFavorite, error) {
+	var favs []UserFavorite
+	err := d.db.WithContext(ctx).
+		Where("user_id = ? AND id > ?", uid, cursor).
+		Order("id").Limit(limit).Find(&favs).Error
+
+	return favs, err
+}
+
+// CountEraseTasks 统计处于这些状态的擦除任务数
+func (d *FavoriteReadDao) CountEraseTasks(ctx context.Context, statuses []uint8) (int64, error) {
+	var count int64
+	err := d.db.WithContext(ctx).Model(&EraseTask{}).Where("status IN ?", statuses).Count(&count).Error
+
+	return count, err
+}
@@ -0,0 +1,93 @@
+package dao
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/crazyfrankie/favorite/pkg/constants"
+)
+
+// SaveCountHistory 写入点赞数快照, 同一时间段内以最后一次为准
+func (d *FavoriteWriteDao) SaveCountHistory(ctx context.Context, rows []FavoriteCountHistory) error {
+	if len(rows) == 0 {
+		return nil
+	}
+
+	return d.db.WithContext(ctx).Clauses(
+		clause.OnConflict{
+			Columns:   []clause.Column{{Name: "biz"}, {Name: "biz_id"}, {Name: "granularity"}, {Name: "bucket"}},
+			DoUpdates: clause.AssignmentColumns([]string{"count", "utime"}),
+		},
+	).CreateInBatches(rows, 500).Error
+}
+
+// RollupCountHistory 将 [start, end) 内的小时快照汇总为天快照, 取每天最后一个小时快照的值
+func (d *FavoriteWriteDao) RollupCountHistory(ctx context.Context, start, end int64) error {
+	now := time.Now().Unix()
+
+	return d.db.WithContext(ctx).Exec(
+		"INSERT INTO favorite_count_history (biz, biz_id, granularity, bucket, count, ctime, utime) "+
+			"SELECT h.biz, h.biz_id, ?, t.day, h.count, ?, ? FROM favorite_count_history h "+
+			"JOIN (SELECT biz, biz_id, bucket - MOD(bucket, 86400) AS day, MAX(bucket) AS last "+
+			"FROM favorite_count_history WHERE granularity = ? AND bucket >= ? AND bucket < ? "+
+			"GROUP BY biz, biz_id, day) t "+
+			"ON h.biz = t.biz AND h.biz_id = t.biz_id AND h.granularity = ? AND h.bucket = t.last "+
+			"ON DUPLICATE KEY UPDATE count = VALUES(count), utime = VALUES(utime)",
+		constants.HistoryGranularityDay, now, now,
+		constants.HistoryGranularityHour, start, end, constants.HistoryGranularityHour).Error
+}
+
+// DeleteCountHistory 分批删除早于 before 的快照
+func (d *FavoriteWriteDao) DeleteCountHistory(ctx context.Context, granularity uint8, before int64, batchSize int) (int64, error) {
+	var deleted int64
+	for {
+		res := d.db.WithContext(ctx).
+			Where("granularity = ? AND bucket < ?", granularity, before).
+			Limit(batchSize).
+			Delete(&FavoriteCountHistory{})
+		if res.Error != nil {
+			return deleted, res.Error
+		}
+		deleted += res.RowsAffected
+		if res.RowsAffected < int64(batchSize) {
+			return deleted, nil
+		}
+	}
+}
+
+// ListCountHistory 按时间顺序获取 [from, to) 内的快照
+func (d *FavoriteReadDao) ListCountHistory(ctx context.Context, biz string, bizId int64, granularity uint8, from, to int64) ([]FavoriteCountHistory, error) {
+	var rows []FavoriteCountHistory
+	err := d.db.WithContext(ctx).
+		Where("biz = ? AND biz_id = ? AND granularity = ? AND bucket >= ? AND bucket < ?", biz, bizId, granularity, from, to).
+		Order("bucket").Find(&rows).Error
+
+	return rows, err
+}
+
+// ListSnapshotted 获取 bizIds 中已有该粒度快照的内容
+func (d *FavoriteReadDao) ListSnapshotted(ctx context.Context, biz string, bizIds []int64, granularity uint8) ([]int64, error) {
+	var res []int64
+	err := d.db.WithContext(ctx).Model(&FavoriteCountHistory{}).Distinct("biz_id").
+		Where("biz = ? AND biz_id IN ? AND granularity = ?", biz, bizIds, granularity).
+		Pluck("biz_id", &res).Error
+
+	return res, err
+}
+
+// LastCountBefore 获取 before 之前最近的一个快照, 不存在时返回 false
+func (d *FavoriteReadDao) LastCountBefore(ctx context.Context, biz string, bizId int64, granularity uint8, before int64) (FavoriteCountHistory, bool, error) {
+	var row FavoriteCountHistory
+	err := d.db.WithContext(ctx).
+		Where("biz = ? AND biz_id = ? AND granularity = ? AND bucket < ?", biz, bizId, granularity, before).
+		Order("bucket DESC").First(&row).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return row, false, nil
+	}
+
+	return row, err == nil, err
+}
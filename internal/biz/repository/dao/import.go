@@ -0,0 +1,63 @@
+package dao
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm/clause"
+
+	"github.com/crazyfrankie/favorite/pkg/constants"
+)
+
+// ImportUserFavorites 批量写入历史点赞, 已存在的记录保持不变, 返回实际写入的条数
+func (d *FavoriteWriteDao) ImportUserFavorites(ctx context.Context, favs []UserFavorite) (int64, error) {
+	if len(favs) == 0 {
+		return 0, nil
+	}
+
+	res := d.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).
+		CreateInBatches(&favs, len(favs))
+
+	return res.RowsAffected, res.Error
+}
+
+// RecountFavorites 根据点赞记录重新计算内容的点赞数
+func (d *FavoriteWriteDao) RecountFavorites(ctx context.Context, biz string, bizIds []int64) error {
+	if len(bizIds) == 0 {
+		return nil
+	}
+
+	now := time.Now().Unix()
+	return d.db.WithContext(ctx).Exec("INSERT INTO favorite_count (biz, biz_id, count, ctime, utime) "+
+		"SELECT biz, biz_id, COUNT(*), ?, ? FROM user_favorite "+
+		"WHERE status = ? AND biz = ? AND biz_id IN ? GROUP BY biz, biz_id "+
+		"ON DUPLICATE KEY UPDATE count = VALUES(count), utime = VALUES(utime)",
+		now, now, constants.FavoriteStatusLiked, biz, bizIds).Error
+}
+
+// ListLikedFavorites 获取 favs 中在数据库里处于点赞状态的记录, 导入时已存在的记录以数据库为准
+func (d *FavoriteReadDao) ListLikedFavorites(ctx context.Context, favs []UserFavorite) ([]UserFavorite, error) {
+	if len(favs) == 0 {
+		return nil, nil
+	}
+
+	tuples := make([][]any, 0, len(favs))
+	for _, f := range favs {
+		tuples = append(tuples, []any{f.UserId, f.Biz, f.BizId})
+	}
+
+	var res []UserFavorite
+	err := d.db.WithContext(ctx).
+		Where("(user_id, biz, biz_id) IN ? AND status = ?", tuples, constants.FavoriteStatusLiked).
+		Find(&res).Error
+
+	return res, err
+}
+
+// ListFavoriteCounts 批量获取内容的点赞数
+func (d *FavoriteReadDao) ListFavoriteCounts(ctx context.Context, biz string, bizIds []int64) ([]FavoriteCount, error) {
+	var counts []FavoriteCount
+	err := d.db.WithContext(ctx).Where("biz = ? AND biz_id IN ?", biz, bizIds).Find(&counts).Error
+
+	return counts, err
+}
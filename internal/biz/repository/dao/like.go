@@ -2,11 +2,17 @@ package dao
 
 import (
 	"context"
+	"errors"
 	"gorm.io/gorm/clause"
 
 	"gorm.io/gorm"
 
 	"github.com/crazyfrankie/favorite/internal/biz/domain"
+	"github.com/crazyfrankie/favorite/pkg/constants"
+)
+
+var (
+	ErrRecordNotFound = errors.New("record not found")
 )
 
 type FavoriteWriteDao struct {
@@ -18,6 +24,10 @@ func NewFavoriteWriteDao(db *gorm.DB) *FavoriteWriteDao {
 }
 
 func (d *FavoriteWriteDao) SaveFavoriteCounts(ctx context.Context, counts []domain.FavoriteCount) error {
+	if len(counts) == 0 {
+		return nil
+	}
+
 	cnts := make([]FavoriteCount, len(counts))
 	for i, c := range counts {
 		cnts[i] = FavoriteCount{
@@ -43,6 +53,26 @@ func (d *FavoriteWriteDao) SaveFavoriteCounts(ctx context.Context, counts []doma
 	return err
 }
 
+// SavePrivacy 更新用户的隐私设置
+func (d *FavoriteWriteDao) SavePrivacy(ctx context.Context, uid int64, visibility uint8) error {
+	return d.db.WithContext(ctx).Clauses(
+		clause.OnConflict{
+			Columns:   []clause.Column{{Name: "user_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"visibility", "utime"}),
+		},
+	).Create(&UserPrivacy{UserId: uid, Visibility: visibility}).Error
+}
+
+// UpsertUserFavorite 写入用户点赞状态, 同一用户对同一内容只保留一条记录
+func (d *FavoriteWriteDao) UpsertUserFavorite(ctx context.Context, uid int64, biz string, bizId int64, status uint8) error {
+	return d.db.WithContext(ctx).Clauses(
+		clause.OnConflict{
+			Columns:   []clause.Column{{Name: "user_id"}, {Name: "biz"}, {Name: "biz_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"status", "utime"}),
+		},
+	).Create(&UserFavorite{UserId: uid, Biz: biz, BizId: bizId, Status: status}).Error
+}
+
 type FavoriteReadDao struct {
 	db *gorm.DB
 }
@@ -50,3 +80,41 @@ type FavoriteReadDao struct {
 func NewFavoriteReadDao(db *gorm.DB) *FavoriteReadDao {
 	return &FavoriteReadDao{db: db}
 }
+
+// GetPrivacy 批量获取用户的隐私设置, 未设置的用户不会出现在结果中
+func (d *FavoriteReadDao) GetPrivacy(ctx context.Context, uids []int64) (map[int64]uint8, error) {
+	var privacies []UserPrivacy
+	err := d.db.WithContext(ctx).Where("user_id IN ?", uids).Find(&privacies).Error
+	if err != nil {
+		return nil, err
+	}
+
+	res := make(map[int64]uint8, len(privacies))
+	for _, p := range privacies {
+		res[p.UserId] = p.Visibility
+	}
+
+	return res, nil
+}
+
+// ListUnFavorites 按取消时间倒序分页获取用户的取消点赞记录
+func (d *FavoriteReadDao) ListUnFavorites(ctx context.Context, uid int64, offset, limit int) ([]UserFavorite, error) {
+	var favs []UserFavorite
+	err := d.db.WithContext(ctx).
+		Where("user_id = ? AND status = ?", uid, constants.FavoriteStatusUnliked).
+		Order("utime DESC, id DESC").
+		Offset(offset).Limit(limit).
+		Find(&favs).Error
+
+	return favs, err
+}
+
+// ListBizUsers 获取内容当前的全部点赞用户
+func (d *FavoriteReadDao) ListBizUsers(ctx context.Context, biz string, bizId int64) ([]int64, error) {
+	var uids []int64
+	err := d.db.WithContext(ctx).Model(&UserFavorite{}).
+		Where("biz = ? AND biz_id = ? AND status = ?", biz, bizId, constants.FavoriteStatusLiked).
+		Pluck("user_id", &uids).Error
+
+	return uids, err
+}
@@ -0,0 +1,20 @@
+package dao
+
+import (
+	"gorm.io/gorm"
+)
+
+const userFavoriteTable = "user_favorite"
+
+// DedupeUserFavorites 在创建唯一索引 uk_uid_biz 之前合并重复的点赞记录, 每组只保留最后更新的一条;
+// 需在 AutoMigrate 之前执行, 否则存在重复数据时索引创建失败
+func DedupeUserFavorites(db *gorm.DB) error {
+	m := db.Migrator()
+	if !m.HasTable(&UserFavorite{}) || m.HasIndex(&UserFavorite{}, "uk_uid_biz") {
+		return nil
+	}
+
+	return db.Exec("DELETE f FROM `" + userFavoriteTable + "` f JOIN `" + userFavoriteTable + "` g " +
+		"ON f.user_id = g.user_id AND f.biz = g.biz AND f.biz_id = g.biz_id " +
+		"AND (f.utime < g.utime OR (f.utime = g.utime AND f.id < g.id))").Error
+}
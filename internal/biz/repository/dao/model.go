@@ -11,10 +11,80 @@ type FavoriteCount struct {
 
 type UserFavorite struct {
 	Id     int64  `gorm:"primaryKey,autoIncrement"`
-	UserId int64  `gorm:"index:idx_uid"` // 用户 ID
-	Biz    string `gorm:"index:idx_biz;type:varchar(128)"`
-	BizId  int64  `gorm:"index:idx_biz"`
+	UserId int64  `gorm:"index:idx_uid;uniqueIndex:uk_uid_biz"` // 用户 ID
+	Biz    string `gorm:"index:idx_biz;uniqueIndex:uk_uid_biz;type:varchar(128)"`
+	BizId  int64  `gorm:"index:idx_biz;uniqueIndex:uk_uid_biz"`
 	Status uint8  `gorm:"not null;default:1"` // 0: 取消点赞, 1: 点赞
 	Ctime  int64  `gorm:"autoCreateTime"`
 	Utime  int64  `gorm:"autoUpdateTime"`
 }
+
+// UserPrivacy 用户点赞列表的隐私设置
+type UserPrivacy struct {
+	Id         int64 `gorm:"primaryKey,autoIncrement"`
+	UserId     int64 `gorm:"uniqueIndex:uk_uid"`
+	Visibility uint8 `gorm:"not null;default:0"` // 0: 公开, 1: 仅关注者, 2: 仅自己
+	Ctime      int64 `gorm:"autoCreateTime"`
+	Utime      int64 `gorm:"autoUpdateTime"`
+}
+
+// EraseTask 用户注销时的点赞数据擦除任务, 记录执行进度以便中断后续跑
+type EraseTask struct {
+	Id         int64  `gorm:"primaryKey,autoIncrement"`
+	UserId     int64  `gorm:"uniqueIndex:uk_uid"`
+	Status     uint8  `gorm:"index:idx_status;not null;default:0"` // 0: 等待, 1: 执行中, 2: 完成, 3: 失败
+	Cursor     int64  `gorm:"not null;default:0"`                  // 已处理到的 user_favorite 主键
+	Processed  int64  `gorm:"not null;default:0"`                  // 已擦除的点赞数
+	Error      string `gorm:"type:varchar(512)"`
+	Owner      string `gorm:"type:varchar(32)"`   // 领取任务的执行者
+	LeaseUntil int64  `gorm:"not null;default:0"` // 执行租约到期时间, 过期后其他实例可重新领取
+	Ctime      int64  `gorm:"autoCreateTime"`
+	Utime      int64  `gorm:"autoUpdateTime"`
+	FinishedAt int64
+}
+
+// FavoriteActionLog 点赞操作流水, 只追加不修改, 按月分区; 用户注销时随点赞一并擦除
+type FavoriteActionLog struct {
+	Id         int64  `gorm:"primaryKey,autoIncrement"`
+	UserId     int64  `gorm:"index:idx_uid_ctime"`
+	Biz        string `gorm:"index:idx_biz_ctime;type:varchar(128)"`
+	BizId      int64  `gorm:"index:idx_biz_ctime"`
+	ActionType uint8  // 1: 点赞, 2: 取消点赞
+	CallerId   int64  // 已认证的调用方, 0 表示内部调用
+	ClientIp   string `gorm:"type:varchar(64)"`
+	TraceId    string `gorm:"type:varchar(32)"`
+	Ctime      int64  `gorm:"primaryKey;index:idx_uid_ctime;index:idx_biz_ctime"` // 毫秒时间戳, 同时作为分区键
+}
+
+// RelatedContent 离线计算的相关内容, 每次计算写入新版本后删除旧版本
+type RelatedContent struct {
+	Id        int64   `gorm:"primaryKey,autoIncrement"`
+	Biz       string  `gorm:"index:idx_biz_item;index:idx_biz_version;type:varchar(128)"`
+	BizId     int64   `gorm:"index:idx_biz_item"`
+	RelatedId int64   `gorm:"not null"`
+	Score     float64 `gorm:"index:idx_biz_item;not null"`
+	Version   int64   `gorm:"index:idx_biz_version;not null"` // 计算批次, 毫秒时间戳
+	Ctime     int64   `gorm:"autoCreateTime"`
+}
+
+// ContentOwner 内容归属, 用于维护创作者获赞数
+type ContentOwner struct {
+	Id      int64  `gorm:"primaryKey,autoIncrement"`
+	Biz     string `gorm:"uniqueIndex:uk_biz_id;index:idx_owner;type:varchar(128)"`
+	BizId   int64  `gorm:"uniqueIndex:uk_biz_id"`
+	OwnerId int64  `gorm:"index:idx_owner;not null"`
+	Ctime   int64  `gorm:"autoCreateTime"`
+	Utime   int64  `gorm:"autoUpdateTime"`
+}
+
+// FavoriteCountHistory 点赞数快照, 只在点赞数变化时记录, 小时快照按天汇总后定期清理
+type FavoriteCountHistory struct {
+	Id          int64  `gorm:"primaryKey,autoIncrement"`
+	Biz         string `gorm:"uniqueIndex:uk_biz_bucket;type:varchar(128)"`
+	BizId       int64  `gorm:"uniqueIndex:uk_biz_bucket"`
+	Granularity uint8  `gorm:"uniqueIndex:uk_biz_bucket;index:idx_granularity_bucket"` // 1: 小时, 2: 天
+	Bucket      int64  `gorm:"uniqueIndex:uk_biz_bucket;index:idx_granularity_bucket"` // 时间段起点, 秒级时间戳
+	Count       int64  `gorm:"not null;default:0"`
+	Ctime       int64  `gorm:"autoCreateTime"`
+	Utime       int64  `gorm:"autoUpdateTime"`
+}
@@ -0,0 +1,29 @@
+package dao
+
+import (
+	"context"
+
+	"gorm.io/gorm/clause"
+)
+
+// SaveContentOwner 写入内容归属, 已存在时更新创作者
+func (d *FavoriteWriteDao) SaveContentOwner(ctx context.Context, biz string, bizId, ownerId int64) error {
+	return d.db.WithContext(ctx).Clauses(
+		clause.OnConflict{
+			Columns:   []clause.Column{{Name: "biz"}, {Name: "biz_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"owner_id", "utime"}),
+		},
+	).Create(&ContentOwner{Biz: biz, BizId: bizId, OwnerId: ownerId}).Error
+}
+
+// SumCreatorCount 根据已落库的点赞数统计创作者在某个业务下的获赞总数
+func (d *FavoriteReadDao) SumCreatorCount(ctx context.Context, ownerId int64, biz string) (int64, error) {
+	var count int64
+	err := d.db.WithContext(ctx).Table("content_owner AS o").
+		Select("COALESCE(SUM(c.count), 0)").
+		Joins("JOIN favorite_count AS c ON c.biz = o.biz AND c.biz_id = o.biz_id").
+		Where("o.owner_id = ? AND o.biz = ?", ownerId, biz).
+		Scan(&count).Error
+
+	return count, err
+}
@@ -0,0 +1,71 @@
+package dao
+
+import (
+	"context"
+
+	"github.com/crazyfrankie/favorite/pkg/constants"
+)
+
+// ListBizFavorites 按 (user_id, biz_id) 游标分页获取某个业务的全部有效点赞, 同一用户的点赞相邻返回
+func (d *FavoriteReadDao) ListBizFavorites(ctx context.Context, biz string, uid, bizId int64, limit int) ([]UserFavorite, error) {
+	var favs []UserFavorite
+	err := d.db.WithContext(ctx).
+		Select("user_id", "biz_id").
+		Where("biz = ? AND status = ? AND (user_id > ? OR (user_id = ? AND biz_id > ?))", biz, constants.FavoriteStatusLiked, uid, uid, bizId).
+		Order("user_id, biz_id").Limit(limit).Find(&favs).Error
+
+	return favs, err
+}
+
+// SaveRelatedContent 写入一批相关内容
+func (d *FavoriteWriteDao) SaveRelatedContent(ctx context.Context, items []RelatedContent) error {
+	if len(items) == 0 {
+		return nil
+	}
+
+	return d.db.WithContext(ctx).CreateInBatches(items, 500).Error
+}
+
+// DeleteStaleRelatedContent 分批删除早于 version 的计算结果
+func (d *FavoriteWriteDao) DeleteStaleRelatedContent(ctx context.Context, biz string, version int64, batchSize int) error {
+	for {
+		res := d.db.WithContext(ctx).
+			Where("biz = ? AND version < ?", biz, version).
+			Limit(batchSize).
+			Delete(&RelatedContent{})
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected < int64(batchSize) {
+			return nil
+		}
+	}
+}
+
+// ListStaleRelatedItems 按 biz_id 游标分页获取存在早于 version 的计算结果的内容
+func (d *FavoriteReadDao) ListStaleRelatedItems(ctx context.Context, biz string, version, afterBizId int64, limit int) ([]int64, error) {
+	var ids []int64
+	err := d.db.WithContext(ctx).Model(&RelatedContent{}).
+		Where("biz = ? AND version < ? AND biz_id > ?", biz, version, afterBizId).
+		Distinct("biz_id").Order("biz_id").Limit(limit).Pluck("biz_id", &ids).Error
+
+	return ids, err
+}
+
+// ListRelatedContent 获取内容最新一次计算的相关内容, 按相似度倒序
+func (d *FavoriteReadDao) ListRelatedContent(ctx context.Context, biz string, bizId int64, limit int) ([]RelatedContent, error) {
+	var version int64
+	err := d.db.WithContext(ctx).Model(&RelatedContent{}).
+		Where("biz = ? AND biz_id = ?", biz, bizId).
+		Select("COALESCE(MAX(version), 0)").Scan(&version).Error
+	if err != nil || version == 0 {
+		return nil, err
+	}
+
+	var items []RelatedContent
+	err = d.db.WithContext(ctx).
+		Where("biz = ? AND biz_id = ? AND version = ?", biz, bizId, version).
+		Order("score DESC").Limit(limit).Find(&items).Error
+
+	return items, err
+}
@@ -0,0 +1,17 @@
+package dao
+
+import (
+	"context"
+
+	"github.com/crazyfrankie/favorite/pkg/constants"
+)
+
+// FilterBizUsers 从 uids 中筛选出当前点赞了该内容的用户
+func (d *FavoriteReadDao) FilterBizUsers(ctx context.Context, biz string, bizId int64, uids []int64) ([]int64, error) {
+	var res []int64
+	err := d.db.WithContext(ctx).Model(&UserFavorite{}).
+		Where("biz = ? AND biz_id = ? AND status = ? AND user_id IN ?", biz, bizId, constants.FavoriteStatusLiked, uids).
+		Pluck("user_id", &res).Error
+
+	return res, err
+}
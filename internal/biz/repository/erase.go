@@ -0,0 +1,229 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/crazyfrankie/favorite/internal/biz/domain"
+	"github.com/crazyfrankie/favorite/internal/biz/repository/dao"
+	"github.com/crazyfrankie/favorite/pkg/constants"
+)
+
+var (
+	ErrTaskNotFound = dao.ErrRecordNotFound
+	// ErrTaskClaimed 擦除任务正由其他执行者处理
+	ErrTaskClaimed = errors.New("erase task claimed by another worker")
+)
+
+// eraseLease 擦除任务的执行租约, 每处理一批续期一次, 执行者宕机后租约过期即可被重新领取
+const eraseLease = 5 * time.Minute
+
+// actionLogEraseBatch 每次删除的操作流水条数
+const actionLogEraseBatch = 1000
+
+// CreateEraseTask 发起擦除任务, 已完成的任务会被重新执行
+func (r *FavoriteRepo) CreateEraseTask(ctx context.Context, uid int64) (domain.EraseTask, error) {
+	if err := r.write.CreateEraseTask(ctx, uid); err != nil {
+		return domain.EraseTask{}, err
+	}
+
+	task, err := r.read.GetEraseTask(ctx, uid)
+	if err != nil {
+		return domain.EraseTask{}, err
+	}
+	if task.Status == constants.EraseStatusDone {
+		if err := r.write.ResetEraseTask(ctx, uid, constants.EraseStatusPending); err != nil {
+			return domain.EraseTask{}, err
+		}
+		task.Status, task.Cursor, task.Processed, task.Error, task.FinishedAt = constants.EraseStatusPending, 0, 0, "", 0
+	}
+
+	return eraseTaskToDomain(task), nil
+}
+
+// GetEraseTask 获取用户的擦除任务
+func (r *FavoriteRepo) GetEraseTask(ctx context.Context, uid int64) (domain.EraseTask, error) {
+	task, err := r.read.GetEraseTask(ctx, uid)
+	if err != nil {
+		return domain.EraseTask{}, err
+	}
+
+	return eraseTaskToDomain(task), nil
+}
+
+// ListUnfinishedEraseTasks 获取需要继续执行的擦除任务, 正在执行且租约未过期的任务除外
+func (r *FavoriteRepo) ListUnfinishedEraseTasks(ctx context.Context, limit int) ([]int64, error) {
+	tasks, err := r.read.ListClaimableEraseTasks(ctx, time.Now().Unix(), limit)
+	if err != nil {
+		return nil, err
+	}
+
+	uids := make([]int64, 0, len(tasks))
+	for _, t := range tasks {
+		uids = append(uids, t.UserId)
+	}
+
+	return uids, nil
+}
+
+// EraseUserFavorites 领取并执行擦除任务, 擦除用户的全部点赞及操作流水并修正计数,
+// 每批处理后记录进度, 中断后可从断点继续; 任务已被其他执行者领取时返回 ErrTaskClaimed
+func (r *FavoriteRepo) EraseUserFavorites(ctx context.Context, uid int64) error {
+	owner := strconv.FormatUint(rand.Uint64(), 36)
+	now := time.Now()
+	ok, err := r.write.ClaimEraseTask(ctx, uid, owner, now.Unix(), now.Add(eraseLease).Unix())
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrTaskClaimed
+	}
+
+	err = r.eraseUserFavorites(ctx, uid, owner)
+	if err != nil && !errors.Is(err, ErrTaskClaimed) {
+		_, _ = r.write.UpdateClaimedEraseTask(context.WithoutCancel(ctx), uid, owner, map[string]any{
+			"status":      constants.EraseStatusFailed,
+			"error":       truncate(err.Error(), 512),
+			"lease_until": 0,
+		})
+	}
+
+	return err
+}
+
+func (r *FavoriteRepo) eraseUserFavorites(ctx context.Context, uid int64, owner string) error {
+	task, err := r.read.GetEraseTask(ctx, uid)
+	if err != nil {
+		return err
+	}
+	// 更新进度的同时续期租约, 租约已被他人接管时停止执行
+	update := func(updates map[string]any) error {
+		updates["lease_until"] = time.Now().Add(eraseLease).Unix()
+		ok, err := r.write.UpdateClaimedEraseTask(ctx, uid, owner, updates)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return ErrTaskClaimed
+		}
+		return nil
+	}
+
+	cursor, processed := task.Cursor, task.Processed
+
+	// 先处理已落库的点赞
+	batchSize := 100
+	for {
+		favs, err := r.read.ListUserFavorites(ctx, uid, cursor, batchSize)
+		if err != nil {
+			return err
+		}
+		if len(favs) == 0 {
+			break
+		}
+
+		ids := make([]int64, 0, len(favs))
+		for _, f := range favs {
+			if err := r.cache.EraseFavorite(ctx, f.Biz, f.BizId, uid); err != nil {
+				return err
+			}
+			ids = append(ids, f.Id)
+		}
+		if err := r.write.DeleteUserFavorites(ctx, ids); err != nil {
+			return err
+		}
+
+		cursor = ids[len(ids)-1]
+		processed += int64(len(ids))
+		if err := update(map[string]any{
+			"cursor":    cursor,
+			"processed": processed,
+		}); err != nil {
+			return err
+		}
+	}
+
+	// 操作流水中含有客户端 IP 等个人信息, 一并删除
+	for {
+		n, err := r.write.DeleteUserActionLogs(ctx, uid, actionLogEraseBatch)
+		if err != nil {
+			return err
+		}
+		if err := update(map[string]any{}); err != nil {
+			return err
+		}
+		if n < actionLogEraseBatch {
+			break
+		}
+	}
+
+	// 再处理只存在于 Redis 中的点赞
+	members, err := r.cache.UserFavoriteElements(ctx, uid)
+	if err != nil {
+		return err
+	}
+	for _, m := range members {
+		biz, bizId, ok := parseMember(m)
+		if !ok {
+			continue
+		}
+		if err := r.cache.EraseFavorite(ctx, biz, bizId, uid); err != nil {
+			return err
+		}
+		processed++
+	}
+	if err := r.cache.CleanupUserHistory(ctx, uid, 0); err != nil {
+		return err
+	}
+
+	if err := r.write.DeletePrivacy(ctx, uid); err != nil {
+		return err
+	}
+	if err := r.cache.DelPrivacy(ctx, uid); err != nil {
+		return err
+	}
+
+	return update(map[string]any{
+		"status":      constants.EraseStatusDone,
+		"processed":   processed,
+		"error":       "",
+		"finished_at": time.Now().Unix(),
+	})
+}
+
+func eraseTaskToDomain(t dao.EraseTask) domain.EraseTask {
+	return domain.EraseTask{
+		UserId:     t.UserId,
+		Status:     t.Status,
+		Processed:  t.Processed,
+		Error:      t.Error,
+		Ctime:      t.Ctime,
+		FinishedAt: t.FinishedAt,
+	}
+}
+
+// parseMember 解析 "{biz}:{bizId}" 格式的成员
+func parseMember(member string) (string, int64, bool) {
+	idx := strings.LastIndex(member, ":")
+	if idx <= 0 {
+		return "", 0, false
+	}
+	bizId, err := strconv.ParseInt(member[idx+1:], 10, 64)
+	if err != nil {
+		return "", 0, false
+	}
+
+	return member[:idx], bizId, true
+}
+
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+
+	return s[:n]
+}
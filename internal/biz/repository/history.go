@@ -0,0 +1,139 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/crazyfrankie/favorite/internal/biz/domain"
+	"github.com/crazyfrankie/favorite/internal/biz/repository/dao"
+	"github.com/crazyfrankie/favorite/pkg/constants"
+)
+
+const daySeconds = 24 * 3600
+
+// syncedCounts 获取数据库中上次同步的点赞数, 按 biz 和 bizId 索引
+func (r *FavoriteRepo) syncedCounts(ctx context.Context, counts []domain.FavoriteCount) (map[string]map[int64]int64, error) {
+	byBiz := make(map[string][]int64)
+	for _, c := range counts {
+		byBiz[c.Biz] = append(byBiz[c.Biz], c.BizId)
+	}
+
+	synced := make(map[string]map[int64]int64, len(byBiz))
+	for biz, ids := range byBiz {
+		rows, err := r.read.ListFavoriteCounts(ctx, biz, ids)
+		if err != nil {
+			return nil, err
+		}
+		synced[biz] = make(map[int64]int64, len(rows))
+		for _, row := range rows {
+			synced[biz][row.BizId] = row.Count
+		}
+	}
+
+	return synced, nil
+}
+
+// snapshotCounts 为点赞数与数据库中上次同步的值不同, 或还没有小时快照的内容记录快照;
+// 后者保证上线前已有点赞且之后不再变化的内容, 以及快照已过期清理的内容, 仍有历史可查
+func (r *FavoriteRepo) snapshotCounts(ctx context.Context, counts []domain.FavoriteCount, synced map[string]map[int64]int64, bucket int64) error {
+	byBiz := make(map[string][]int64)
+	for _, c := range counts {
+		if old, ok := synced[c.Biz][c.BizId]; ok && old == c.Count {
+			byBiz[c.Biz] = append(byBiz[c.Biz], c.BizId)
+		}
+	}
+	snapshotted := make(map[string]map[int64]bool, len(byBiz))
+	for biz, ids := range byBiz {
+		exists, err := r.read.ListSnapshotted(ctx, biz, ids, constants.HistoryGranularityHour)
+		if err != nil {
+			return err
+		}
+		snapshotted[biz] = make(map[int64]bool, len(exists))
+		for _, id := range exists {
+			snapshotted[biz][id] = true
+		}
+	}
+
+	rows := make([]dao.FavoriteCountHistory, 0, len(counts))
+	for _, c := range counts {
+		if old, ok := synced[c.Biz][c.BizId]; ok && old == c.Count && snapshotted[c.Biz][c.BizId] {
+			continue
+		}
+		rows = append(rows, dao.FavoriteCountHistory{
+			Biz:         c.Biz,
+			BizId:       c.BizId,
+			Granularity: constants.HistoryGranularityHour,
+			Bucket:      bucket,
+			Count:       c.Count,
+		})
+	}
+
+	return r.write.SaveCountHistory(ctx, rows)
+}
+
+// CountHistory 获取 [from, to) 内的点赞数快照及 from 之前最近的一个值
+// 快照只在变化时记录, 缺失的时间段沿用前一个值; 按天查询时尚未汇总的部分由小时快照补齐
+func (r *FavoriteRepo) CountHistory(ctx context.Context, biz string, bizId int64, granularity uint8, from, to int64) (int64, []domain.CountPoint, error) {
+	var base int64
+	last, hasLast, err := r.read.LastCountBefore(ctx, biz, bizId, granularity, from)
+	if err != nil {
+		return 0, nil, err
+	}
+	if hasLast {
+		base = last.Count
+	}
+
+	rows, err := r.read.ListCountHistory(ctx, biz, bizId, granularity, from, to)
+	if err != nil {
+		return 0, nil, err
+	}
+	points := make([]domain.CountPoint, 0, len(rows))
+	for _, row := range rows {
+		points = append(points, domain.CountPoint{Time: row.Bucket, Count: row.Count})
+	}
+	if granularity != constants.HistoryGranularityDay {
+		return base, points, nil
+	}
+
+	// 最近的天快照之后的部分尚未汇总, 取每天最后一个小时快照
+	rest := from
+	if len(points) > 0 {
+		rest = points[len(points)-1].Time + daySeconds
+	}
+	if rest >= to {
+		return base, points, nil
+	}
+	if len(points) == 0 {
+		// from 之前的值可能也还没有汇总
+		lastHour, ok, err := r.read.LastCountBefore(ctx, biz, bizId, constants.HistoryGranularityHour, from)
+		if err != nil {
+			return 0, nil, err
+		}
+		if ok && (!hasLast || lastHour.Bucket >= last.Bucket) {
+			base = lastHour.Count
+		}
+	}
+	hours, err := r.read.ListCountHistory(ctx, biz, bizId, constants.HistoryGranularityHour, rest, to)
+	if err != nil {
+		return 0, nil, err
+	}
+	for _, h := range hours {
+		day := h.Bucket - h.Bucket%daySeconds
+		if n := len(points); n > 0 && points[n-1].Time == day {
+			points[n-1].Count = h.Count
+			continue
+		}
+		points = append(points, domain.CountPoint{Time: day, Count: h.Count})
+	}
+
+	return base, points, nil
+}
+
+// RollupCountHistory 将 [start, end) 内的小时快照汇总为天快照
+func (r *FavoriteRepo) RollupCountHistory(ctx context.Context, start, end int64) error {
+	return r.write.RollupCountHistory(ctx, start, end)
+}
+
+// DeleteCountHistory 删除早于 before 的快照
+func (r *FavoriteRepo) DeleteCountHistory(ctx context.Context, granularity uint8, before int64) (int64, error) {
+	return r.write.DeleteCountHistory(ctx, granularity, before, 1000)
+}
@@ -0,0 +1,75 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/crazyfrankie/favorite/internal/biz/domain"
+	"github.com/crazyfrankie/favorite/internal/biz/repository/dao"
+	"github.com/crazyfrankie/favorite/pkg/constants"
+)
+
+// ImportFavorites 写入一批历史点赞, 返回实际写入的条数
+func (r *FavoriteRepo) ImportFavorites(ctx context.Context, records []domain.ImportRecord, rebuildCache bool) (int64, error) {
+	favs := make([]dao.UserFavorite, 0, len(records))
+	for _, rec := range records {
+		favs = append(favs, dao.UserFavorite{
+			UserId: rec.UserId,
+			Biz:    rec.Biz,
+			BizId:  rec.BizId,
+			Status: constants.FavoriteStatusLiked,
+			Ctime:  rec.LikedAt,
+			Utime:  rec.LikedAt,
+		})
+	}
+
+	imported, err := r.write.ImportUserFavorites(ctx, favs)
+	if err != nil {
+		return 0, err
+	}
+	if !rebuildCache {
+		return imported, nil
+	}
+
+	// 已存在的记录可能已取消点赞, 只有数据库中处于点赞状态的记录才写入缓存
+	liked, err := r.read.ListLikedFavorites(ctx, favs)
+	if err != nil {
+		return imported, err
+	}
+	cached := make([]domain.ImportRecord, 0, len(liked))
+	for _, f := range liked {
+		cached = append(cached, domain.ImportRecord{
+			UserId:  f.UserId,
+			Biz:     f.Biz,
+			BizId:   f.BizId,
+			LikedAt: f.Ctime,
+		})
+	}
+	if err := r.cache.ImportFavorites(ctx, cached); err != nil {
+		return imported, err
+	}
+
+	return imported, nil
+}
+
+// RecountFavorites 根据点赞记录重新计算内容点赞数并覆盖缓存计数,
+// 缓存计数会被定时同步写回数据库, 不覆盖会把重算结果冲掉
+func (r *FavoriteRepo) RecountFavorites(ctx context.Context, biz string, bizIds []int64) error {
+	if err := r.write.RecountFavorites(ctx, biz, bizIds); err != nil {
+		return err
+	}
+
+	counts, err := r.read.ListFavoriteCounts(ctx, biz, bizIds)
+	if err != nil {
+		return err
+	}
+	res := make([]domain.FavoriteCount, 0, len(counts))
+	for _, c := range counts {
+		res = append(res, domain.FavoriteCount{
+			Count: c.Count,
+			Biz:   c.Biz,
+			BizId: c.BizId,
+		})
+	}
+
+	return r.cache.SetFavoriteCounts(ctx, res)
+}
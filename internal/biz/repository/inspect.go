@@ -0,0 +1,86 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/crazyfrankie/favorite/internal/biz/domain"
+)
+
+// InspectContent 获取内容在缓存与数据库中的点赞数及点赞用户
+func (r *FavoriteRepo) InspectContent(ctx context.Context, biz string, bizId int64) (domain.ContentSnapshot, error) {
+	var (
+		snap domain.ContentSnapshot
+		err  error
+	)
+
+	if snap.CacheCount, err = r.cache.FavoriteCount(ctx, biz, bizId); err != nil {
+		return snap, err
+	}
+	if snap.CacheUsers, err = r.cache.BizFavoriteUser(ctx, biz, bizId); err != nil {
+		return snap, err
+	}
+
+	counts, err := r.read.ListFavoriteCounts(ctx, biz, []int64{bizId})
+	if err != nil {
+		return snap, err
+	}
+	if len(counts) > 0 {
+		snap.DBCount = counts[0].Count
+	}
+	if snap.DBUsers, err = r.read.ListBizUsers(ctx, biz, bizId); err != nil {
+		return snap, err
+	}
+
+	return snap, nil
+}
+
+// InspectUser 获取用户在缓存与数据库中的点赞记录
+func (r *FavoriteRepo) InspectUser(ctx context.Context, uid int64) (domain.UserSnapshot, error) {
+	var (
+		snap domain.UserSnapshot
+		err  error
+	)
+
+	if snap.CacheItems, err = r.cache.UserFavoriteElements(ctx, uid); err != nil {
+		return snap, err
+	}
+
+	var cursor int64
+	for {
+		favs, next, err := r.UserFavoritesPage(ctx, uid, cursor, 500)
+		if err != nil {
+			return snap, err
+		}
+		for _, f := range favs {
+			snap.DBItems = append(snap.DBItems, fmt.Sprintf("%s:%d", f.Biz, f.BizId))
+		}
+		if next == 0 {
+			return snap, nil
+		}
+		cursor = next
+	}
+}
+
+// ResyncContent 以数据库中的点赞记录为准, 重建内容的点赞数及缓存中的点赞用户
+func (r *FavoriteRepo) ResyncContent(ctx context.Context, biz string, bizId int64) error {
+	uids, err := r.read.ListBizUsers(ctx, biz, bizId)
+	if err != nil {
+		return err
+	}
+	if err := r.cache.ResetBizUsers(ctx, biz, bizId, uids); err != nil {
+		return err
+	}
+
+	counts := []domain.FavoriteCount{{Count: int64(len(uids)), Biz: biz, BizId: bizId}}
+	if err := r.write.SaveFavoriteCounts(ctx, counts); err != nil {
+		return err
+	}
+
+	return r.cache.SetFavoriteCounts(ctx, counts)
+}
+
+// BizTypes 获取所有业务类型
+func (r *FavoriteRepo) BizTypes(ctx context.Context) ([]string, error) {
+	return r.cache.BizTypes(ctx)
+}
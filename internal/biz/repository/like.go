@@ -2,10 +2,14 @@ package repository
 
 import (
 	"context"
+	"time"
+
+	"go.uber.org/zap"
 
 	"github.com/crazyfrankie/favorite/internal/biz/domain"
 	"github.com/crazyfrankie/favorite/internal/biz/repository/cache"
 	"github.com/crazyfrankie/favorite/internal/biz/repository/dao"
+	"github.com/crazyfrankie/favorite/pkg/constants"
 )
 
 var (
@@ -29,12 +33,55 @@ func NewFavoriteRepo(c *cache.FavoriteCache, write *dao.FavoriteWriteDao, read *
 
 // CreateFavorite 创建点赞记录及递增点赞数
 func (r *FavoriteRepo) CreateFavorite(ctx context.Context, biz string, bizId, uid int64) error {
-	return r.cache.CreateFavorite(ctx, biz, bizId, uid)
+	if err := r.cache.CreateFavorite(ctx, biz, bizId, uid); err != nil {
+		return err
+	}
+
+	return r.write.UpsertUserFavorite(ctx, uid, biz, bizId, constants.FavoriteStatusLiked)
 }
 
 // DeleteFavorite 删除点赞记录及递减点赞数
 func (r *FavoriteRepo) DeleteFavorite(ctx context.Context, biz string, bizId, uid int64) error {
-	return r.cache.DeleteFavorite(ctx, biz, bizId, uid)
+	if err := r.cache.DeleteFavorite(ctx, biz, bizId, uid); err != nil {
+		return err
+	}
+
+	// 保留 Status=0 的记录作为取消点赞历史
+	return r.write.UpsertUserFavorite(ctx, uid, biz, bizId, constants.FavoriteStatusUnliked)
+}
+
+// UserUnFavorites 分页获取用户的取消点赞历史, 数据库不可用时降级读取缓存中最近的记录
+func (r *FavoriteRepo) UserUnFavorites(ctx context.Context, uid int64, offset, limit int64) ([]domain.UnFavorite, error) {
+	favs, err := r.read.ListUnFavorites(ctx, uid, int(offset), int(limit))
+	if err != nil {
+		return r.cache.GetUserUnFavorites(ctx, uid, offset, limit)
+	}
+
+	res := make([]domain.UnFavorite, 0, len(favs))
+	for _, f := range favs {
+		res = append(res, domain.UnFavorite{
+			Biz:       f.Biz,
+			BizId:     f.BizId,
+			UnlikedAt: f.Utime,
+		})
+	}
+
+	return res, nil
+}
+
+// CreateShadowFavorite 创建被反作弊标记的点赞, 不计入公开计数
+func (r *FavoriteRepo) CreateShadowFavorite(ctx context.Context, fav domain.FlaggedFavorite) error {
+	return r.cache.CreateShadowFavorite(ctx, fav)
+}
+
+// ListFlaggedFavorites 获取被标记的点赞
+func (r *FavoriteRepo) ListFlaggedFavorites(ctx context.Context, offset, limit int64) ([]domain.FlaggedFavorite, int64, error) {
+	return r.cache.ListFlaggedFavorites(ctx, offset, limit)
+}
+
+// PurgeFlaggedFavorite 清除被标记的点赞
+func (r *FavoriteRepo) PurgeFlaggedFavorite(ctx context.Context, biz string, bizId, uid int64) (bool, error) {
+	return r.cache.PurgeFlaggedFavorite(ctx, biz, bizId, uid)
 }
 
 // FavoriteCount 获取单个内容的点赞总数
@@ -54,7 +101,13 @@ func (r *FavoriteRepo) UserFavoriteCount(ctx context.Context, uid int64) (int64,
 
 // UserFavoriteElements 用户点赞的内容 ID 集合
 func (r *FavoriteRepo) UserFavoriteElements(ctx context.Context, uid int64) ([]string, error) {
-	return r.cache.UserFavoriteElements(ctx, uid)
+	res, err := r.cache.UserFavoriteElements(ctx, uid)
+	if err != nil {
+		return nil, err
+	}
+
+	// 惰性过滤已被删除的内容
+	return r.cache.FilterPurged(ctx, res)
 }
 
 // UserFavoritedCount 获取用户的内容被点赞总数
@@ -67,17 +120,111 @@ func (r *FavoriteRepo) IsUserFavorite(ctx context.Context, biz string, uid, bizI
 	return r.cache.IsUserFavorite(ctx, biz, uid, bizId)
 }
 
+// BatchIsUserFavorite 批量判断用户是否点赞了内容
+func (r *FavoriteRepo) BatchIsUserFavorite(ctx context.Context, favs []domain.UserFavorite) ([]bool, error) {
+	return r.cache.BatchIsUserFavorite(ctx, favs)
+}
+
+// BatchFavoriteCount 批量获取内容的点赞数
+func (r *FavoriteRepo) BatchFavoriteCount(ctx context.Context, contents []domain.FavoriteCount) ([]int64, error) {
+	return r.cache.BatchFavoriteCount(ctx, contents)
+}
+
 // GetTopFavoriteContent 点赞数排行榜
 func (r *FavoriteRepo) GetTopFavoriteContent(ctx context.Context, biz string, topN int64) ([]int64, error) {
 	return r.cache.GetTopFavoriteContent(ctx, biz, topN)
 }
 
-// SyncFavoritesCount 将内容点赞总数同步到数据库
-func (r *FavoriteRepo) SyncFavoritesCount(ctx context.Context) error {
+// GetPrivacy 批量获取用户隐私设置, 未设置的用户默认公开
+func (r *FavoriteRepo) GetPrivacy(ctx context.Context, uids []int64) (map[int64]uint8, error) {
+	if len(uids) == 0 {
+		return map[int64]uint8{}, nil
+	}
+
+	res, missing, err := r.cache.GetPrivacy(ctx, uids)
+	if err != nil {
+		// 缓存不可用时直接回源
+		res, missing = make(map[int64]uint8, len(uids)), uids
+	}
+	if len(missing) == 0 {
+		return res, nil
+	}
+
+	fromDB, err := r.read.GetPrivacy(ctx, missing)
+	if err != nil {
+		return nil, err
+	}
+	backfill := make(map[int64]uint8, len(missing))
+	for _, uid := range missing {
+		// 未设置时同样回写, 避免缓存穿透
+		backfill[uid] = fromDB[uid]
+		res[uid] = fromDB[uid]
+	}
+	_ = r.cache.SetPrivacy(ctx, backfill)
+
+	return res, nil
+}
+
+// SetPrivacy 更新用户隐私设置
+func (r *FavoriteRepo) SetPrivacy(ctx context.Context, uid int64, visibility uint8) error {
+	if err := r.write.SavePrivacy(ctx, uid, visibility); err != nil {
+		return err
+	}
+
+	return r.cache.DelPrivacy(ctx, uid)
+}
+
+// UserFavoritesPage 按主键游标分页获取用户已落库的点赞, 同时返回下一页游标, 游标为 0 表示没有更多数据
+func (r *FavoriteRepo) UserFavoritesPage(ctx context.Context, uid, cursor int64, limit int) ([]domain.UserFavorite, int64, error) {
+	favs, err := r.read.ListUserFavorites(ctx, uid, cursor, limit)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	res := make([]domain.UserFavorite, 0, len(favs))
+	for _, f := range favs {
+		if f.Status != constants.FavoriteStatusLiked {
+			continue
+		}
+		res = append(res, domain.UserFavorite{
+			UserId: f.UserId,
+			Biz:    f.Biz,
+			BizId:  f.BizId,
+			Status: f.Status,
+			Ctime:  f.Ctime,
+			Utime:  f.Utime,
+		})
+	}
+
+	var next int64
+	if len(favs) == limit {
+		next = favs[len(favs)-1].Id
+	}
+
+	return res, next, nil
+}
+
+// SyncFavoritesCount 将内容点赞总数同步到数据库, 同时为点赞数发生变化的内容记录小时快照
+// threshold > 0 时, 数据库中已有记录且变化量小于 threshold 的内容本轮不同步
+func (r *FavoriteRepo) SyncFavoritesCount(ctx context.Context, threshold int64) error {
 	countStream, err := r.cache.GetAllCount(ctx)
 	if err != nil {
 		return err
 	}
+	bucket := time.Now().Truncate(time.Hour).Unix()
+
+	flush := func(batch []domain.FavoriteCount) error {
+		synced, err := r.syncedCounts(ctx, batch)
+		if err != nil {
+			return err
+		}
+		// 快照需在覆盖数据库计数之前记录; 快照只用于趋势展示, 失败不影响计数同步
+		if err := r.snapshotCounts(ctx, batch, synced, bucket); err != nil {
+			zap.L().Error("snapshot favorite counts failed", zap.Int64("bucket", bucket), zap.Error(err))
+		}
+
+		return r.write.SaveFavoriteCounts(ctx, changedCounts(batch, synced, threshold))
+	}
 
 	// 设置批量提交大小，避免频繁写入
 	batchSize := 50
@@ -89,7 +236,7 @@ func (r *FavoriteRepo) SyncFavoritesCount(ctx context.Context) error {
 
 		// 如果达到批量大小, 就进行批量写入
 		if len(batch) >= batchSize {
-			if err := r.write.SaveFavoriteCounts(ctx, batch); err != nil {
+			if err := flush(batch); err != nil {
 				return err
 			}
 			// 清空 batch
@@ -99,10 +246,41 @@ func (r *FavoriteRepo) SyncFavoritesCount(ctx context.Context) error {
 
 	// 处理最后剩余的数据
 	if len(batch) > 0 {
-		if err := r.write.SaveFavoriteCounts(ctx, batch); err != nil {
+		if err := flush(batch); err != nil {
 			return err
 		}
 	}
 
-	return nil
+	return r.cache.MarkSynced(ctx, time.Now())
+}
+
+// changedCounts 过滤出需要写入数据库的点赞数, 尚未落库的内容总是写入
+func changedCounts(counts []domain.FavoriteCount, synced map[string]map[int64]int64, threshold int64) []domain.FavoriteCount {
+	if threshold <= 0 {
+		return counts
+	}
+
+	res := make([]domain.FavoriteCount, 0, len(counts))
+	for _, c := range counts {
+		old, ok := synced[c.Biz][c.BizId]
+		if ok && abs(c.Count-old) < threshold {
+			continue
+		}
+		res = append(res, c)
+	}
+
+	return res
+}
+
+func abs(n int64) int64 {
+	if n < 0 {
+		return -n
+	}
+
+	return n
+}
+
+// WatchCounts 订阅点赞数变化, 阻塞直到 ctx 被取消
+func (r *FavoriteRepo) WatchCounts(ctx context.Context, fn func(domain.FavoriteCount)) error {
+	return r.cache.WatchCounts(ctx, fn)
 }
@@ -0,0 +1,27 @@
+package repository
+
+import (
+	"context"
+)
+
+// RegisterContentOwner 登记内容归属, 内容已有的点赞数会计入创作者
+func (r *FavoriteRepo) RegisterContentOwner(ctx context.Context, biz string, bizId, ownerId int64) error {
+	if err := r.write.SaveContentOwner(ctx, biz, bizId, ownerId); err != nil {
+		return err
+	}
+
+	return r.cache.RegisterContentOwner(ctx, biz, bizId, ownerId)
+}
+
+// CreatorReceivedCount 获取创作者在某个业务下收到的点赞总数, 缓存不存在时根据已落库的点赞数统计
+func (r *FavoriteRepo) CreatorReceivedCount(ctx context.Context, ownerId int64, biz string) (int64, error) {
+	count, ok, err := r.cache.CreatorReceivedCount(ctx, ownerId, biz)
+	if err != nil {
+		return 0, err
+	}
+	if ok {
+		return count, nil
+	}
+
+	return r.read.SumCreatorCount(ctx, ownerId, biz)
+}
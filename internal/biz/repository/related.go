@@ -0,0 +1,174 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/crazyfrankie/favorite/internal/biz/domain"
+	"github.com/crazyfrankie/favorite/internal/biz/repository/dao"
+)
+
+const (
+	// 相关内容缓存时长, 需大于计算周期
+	relatedCacheTTL = 48 * time.Hour
+	// 每个内容最多保存的相关内容数
+	MaxRelatedItems = 50
+	// 写入相关内容时每批的内容数
+	relatedSaveBatch = 200
+)
+
+// ScanBizFavorites 按用户遍历某个业务的全部有效点赞, 每个用户回调一次
+func (r *FavoriteRepo) ScanBizFavorites(ctx context.Context, biz string, fn func(uid int64, bizIds []int64) error) error {
+	var (
+		curUid     int64
+		curItems   []int64
+		lastUid    int64
+		lastBizId  int64
+		batchLimit = 2000
+	)
+	for {
+		favs, err := r.read.ListBizFavorites(ctx, biz, lastUid, lastBizId, batchLimit)
+		if err != nil {
+			return err
+		}
+
+		for _, f := range favs {
+			if f.UserId != curUid && len(curItems) > 0 {
+				if err := fn(curUid, curItems); err != nil {
+					return err
+				}
+				curItems = nil
+			}
+			curUid = f.UserId
+			curItems = append(curItems, f.BizId)
+		}
+
+		if len(favs) < batchLimit {
+			break
+		}
+		last := favs[len(favs)-1]
+		lastUid, lastBizId = last.UserId, last.BizId
+	}
+
+	if len(curItems) > 0 {
+		return fn(curUid, curItems)
+	}
+
+	return nil
+}
+
+// SaveRelatedContent 写入一次计算的全部结果, 成功后删除该业务的旧版本
+func (r *FavoriteRepo) SaveRelatedContent(ctx context.Context, biz string, related map[int64][]domain.RelatedItem, version int64) error {
+	chunk := make(map[int64][]domain.RelatedItem, relatedSaveBatch)
+	flush := func() error {
+		rows := make([]dao.RelatedContent, 0, len(chunk)*MaxRelatedItems)
+		for bizId, items := range chunk {
+			for _, item := range items {
+				rows = append(rows, dao.RelatedContent{
+					Biz:       biz,
+					BizId:     bizId,
+					RelatedId: item.BizId,
+					Score:     item.Score,
+					Version:   version,
+				})
+			}
+		}
+		if err := r.write.SaveRelatedContent(ctx, rows); err != nil {
+			return err
+		}
+		if err := r.cache.SetRelatedContent(ctx, biz, chunk, relatedCacheTTL); err != nil {
+			return err
+		}
+		clear(chunk)
+
+		return nil
+	}
+
+	for bizId, items := range related {
+		chunk[bizId] = items
+		if len(chunk) >= relatedSaveBatch {
+			if err := flush(); err != nil {
+				return err
+			}
+		}
+	}
+	if err := flush(); err != nil {
+		return err
+	}
+
+	// 上一版本有结果而本次没有的内容写入空结果, 缓存中不再保留旧结果, 回源时也不必反复查询数据库
+	var after int64
+	for {
+		ids, err := r.read.ListStaleRelatedItems(ctx, biz, version, after, relatedSaveBatch)
+		if err != nil {
+			return err
+		}
+		for _, id := range ids {
+			if _, ok := related[id]; !ok {
+				chunk[id] = nil
+			}
+		}
+		if err := r.cache.SetRelatedContent(ctx, biz, chunk, relatedCacheTTL); err != nil {
+			return err
+		}
+		clear(chunk)
+
+		if len(ids) < relatedSaveBatch {
+			break
+		}
+		after = ids[len(ids)-1]
+	}
+
+	return r.write.DeleteStaleRelatedContent(ctx, biz, version, 1000)
+}
+
+// RelatedContent 获取内容相似度最高的 k 个相关内容, 过滤已删除的内容, 缓存不存在时回源数据库并回填
+func (r *FavoriteRepo) RelatedContent(ctx context.Context, biz string, bizId int64, k int) ([]domain.RelatedItem, error) {
+	res, ok, err := r.cache.RelatedContent(ctx, biz, bizId, int64(k))
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		rows, err := r.read.ListRelatedContent(ctx, biz, bizId, MaxRelatedItems)
+		if err != nil {
+			return nil, err
+		}
+
+		all := make([]domain.RelatedItem, 0, len(rows))
+		for _, row := range rows {
+			all = append(all, domain.RelatedItem{BizId: row.RelatedId, Score: row.Score})
+		}
+		// 没有相关内容时同样回填, 避免每次请求都回源
+		err = r.cache.SetRelatedContent(ctx, biz, map[int64][]domain.RelatedItem{bizId: all}, relatedCacheTTL)
+		if err != nil {
+			return nil, err
+		}
+		res = all[:min(k, len(all))]
+	}
+
+	members := make([]string, 0, len(res))
+	for _, item := range res {
+		members = append(members, fmt.Sprintf("%s:%d", biz, item.BizId))
+	}
+	alive, err := r.cache.FilterPurged(ctx, members)
+	if err != nil {
+		return nil, err
+	}
+	if len(alive) == len(members) {
+		return res, nil
+	}
+
+	keep := make(map[string]struct{}, len(alive))
+	for _, m := range alive {
+		keep[m] = struct{}{}
+	}
+	filtered := make([]domain.RelatedItem, 0, len(alive))
+	for i, item := range res {
+		if _, ok := keep[members[i]]; ok {
+			filtered = append(filtered, item)
+		}
+	}
+
+	return filtered, nil
+}
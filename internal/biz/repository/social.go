@@ -0,0 +1,37 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/crazyfrankie/favorite/internal/biz/repository/cache"
+)
+
+const (
+	// 好友数超过该值时改为查询数据库, 避免写入过大的临时集合
+	friendLikersCacheLimit = 1000
+	// 查询数据库时每批 IN 的用户数
+	friendLikersBatch = 1000
+)
+
+// FriendLikers 获取好友中点赞了该内容的用户, 缓存中没有点赞用户集合时查询数据库
+func (r *FavoriteRepo) FriendLikers(ctx context.Context, biz string, bizId int64, friends []int64) ([]int64, error) {
+	if len(friends) <= friendLikersCacheLimit {
+		res, err := r.cache.FriendLikers(ctx, biz, bizId, friends)
+		if !errors.Is(err, cache.ErrLikersNotCached) {
+			return res, err
+		}
+	}
+
+	res := make([]int64, 0)
+	for start := 0; start < len(friends); start += friendLikersBatch {
+		end := min(start+friendLikersBatch, len(friends))
+		uids, err := r.read.FilterBizUsers(ctx, biz, bizId, friends[start:end])
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, uids...)
+	}
+
+	return res, nil
+}
@@ -0,0 +1,32 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/crazyfrankie/favorite/pkg/constants"
+)
+
+// ActiveLikers 获取最近 window 内点赞过的去重用户数
+func (r *FavoriteRepo) ActiveLikers(ctx context.Context, window time.Duration) (int64, error) {
+	return r.cache.ActiveLikers(ctx, window)
+}
+
+// SampleCounterDrift 抽样统计各业务点赞计数与点赞用户集合的偏差
+func (r *FavoriteRepo) SampleCounterDrift(ctx context.Context, n int) (map[string]int64, error) {
+	return r.cache.SampleCounterDrift(ctx, n)
+}
+
+// LastSynced 获取上次成功同步到数据库的时间
+func (r *FavoriteRepo) LastSynced(ctx context.Context) (time.Time, error) {
+	return r.cache.LastSynced(ctx)
+}
+
+// PendingEraseTasks 统计尚未完成的擦除任务数
+func (r *FavoriteRepo) PendingEraseTasks(ctx context.Context) (int64, error) {
+	return r.read.CountEraseTasks(ctx, []uint8{
+		constants.EraseStatusPending,
+		constants.EraseStatusRunning,
+		constants.EraseStatusFailed,
+	})
+}
@@ -0,0 +1,184 @@
+package service
+
+import (
+	"context"
+	"net"
+	"net/netip"
+	"strings"
+	"time"
+
+	oteltrace "go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+
+	"github.com/crazyfrankie/favorite/api/rpc_gen/favorite"
+	"github.com/crazyfrankie/favorite/internal/biz/domain"
+	"github.com/crazyfrankie/favorite/internal/config"
+	"github.com/crazyfrankie/favorite/pkg/auth"
+	"github.com/crazyfrankie/favorite/pkg/constants"
+)
+
+// QueryActionLogs 管理端: 按时间范围查询用户或内容的操作流水
+func (f *FavoriteServer) QueryActionLogs(ctx context.Context, req *favorite.QueryActionLogsRequest) (*favorite.QueryActionLogsResponse, error) {
+	if req.GetUserId() <= 0 && req.GetBiz() == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "user_id or biz is required")
+	}
+	end := req.GetEnd()
+	if end <= 0 {
+		end = time.Now().UnixMilli()
+	}
+	if req.GetStart() >= end {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid time range: [%d, %d)", req.GetStart(), end)
+	}
+	limit := req.GetLimit()
+	if limit <= 0 || limit > 500 {
+		limit = 100
+	}
+
+	logs, err := f.repo.ListActionLogs(ctx, domain.ActionLogQuery{
+		UserId: req.GetUserId(),
+		Biz:    req.GetBiz(),
+		BizId:  req.GetBizId(),
+		Start:  req.GetStart(),
+		End:    end,
+		Offset: int(req.GetOffset()),
+		Limit:  int(limit),
+	})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to query action logs: %v", err)
+	}
+
+	res := make([]*favorite.ActionLog, 0, len(logs))
+	for _, l := range logs {
+		res = append(res, actionLogToPb(l))
+	}
+
+	return &favorite.QueryActionLogsResponse{Logs: res}, nil
+}
+
+// FavoriteStateAt 管理端: 根据操作流水还原用户在某一时刻是否点赞了某个内容
+func (f *FavoriteServer) FavoriteStateAt(ctx context.Context, req *favorite.FavoriteStateAtRequest) (*favorite.FavoriteStateAtResponse, error) {
+	at := req.GetAt()
+	if at <= 0 {
+		at = time.Now().UnixMilli()
+	}
+
+	log, ok, err := f.repo.LastActionLog(ctx, req.GetUserId(), req.GetBiz(), req.GetBizId(), at)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get action log: %v", err)
+	}
+	if !ok {
+		return &favorite.FavoriteStateAtResponse{}, nil
+	}
+
+	return &favorite.FavoriteStateAtResponse{
+		Favorite:   log.ActionType == constants.FavoriteActionType,
+		LastAction: actionLogToPb(log),
+	}, nil
+}
+
+// MaintainActionLog 预建流水表未来的月分区, 由定时任务调用
+func (f *FavoriteServer) MaintainActionLog(ctx context.Context) error {
+	return f.repo.EnsureActionLogPartitions(ctx, 3)
+}
+
+// recordAction 记录点赞操作流水, 失败时只记录日志, 不影响点赞结果
+func (f *FavoriteServer) recordAction(ctx context.Context, req *favorite.FavoriteActionRequest, at time.Time) {
+	log := domain.ActionLog{
+		UserId:     req.GetUserId(),
+		Biz:        req.GetBiz(),
+		BizId:      req.GetBizId(),
+		ActionType: req.GetActionType(),
+		ClientIp:   clientIP(ctx),
+		Ctime:      at.UnixMilli(),
+	}
+	if caller, ok := auth.CallerFromContext(ctx); ok {
+		log.CallerId = caller
+	}
+	if span := oteltrace.SpanContextFromContext(ctx); span.HasTraceID() {
+		log.TraceId = span.TraceID().String()
+	}
+
+	if err := f.repo.CreateActionLog(ctx, log); err != nil {
+		zap.L().Error("failed to record favorite action", zap.Error(err))
+	}
+}
+
+// clientIP 连接对端为可信代理时使用其透传的真实 IP, 否则使用连接对端地址
+func clientIP(ctx context.Context) string {
+	var addr string
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		host, _, err := net.SplitHostPort(p.Addr.String())
+		if err != nil {
+			host = p.Addr.String()
+		}
+		addr = host
+	}
+
+	trusted := trustedProxies(config.GetConf().Server.TrustedProxies)
+	if !trusted(addr) {
+		return addr
+	}
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return addr
+	}
+	if v := md.Get("x-forwarded-for"); len(v) > 0 && v[0] != "" {
+		// 左侧的条目可由客户端任意填写, 从右往左取第一个不可信的地址
+		hops := strings.Split(strings.Join(v, ","), ",")
+		for i := len(hops) - 1; i >= 0; i-- {
+			hop := strings.TrimSpace(hops[i])
+			if hop != "" && !trusted(hop) {
+				return hop
+			}
+		}
+		return strings.TrimSpace(hops[0])
+	}
+	if v := md.Get("x-real-ip"); len(v) > 0 && v[0] != "" {
+		return v[0]
+	}
+
+	return addr
+}
+
+// trustedProxies 返回判断地址是否属于可信代理的函数, proxies 中无法解析的条目被忽略
+func trustedProxies(proxies []string) func(string) bool {
+	prefixes := make([]netip.Prefix, 0, len(proxies))
+	for _, p := range proxies {
+		if prefix, err := netip.ParsePrefix(p); err == nil {
+			prefixes = append(prefixes, prefix)
+		} else if ip, err := netip.ParseAddr(p); err == nil {
+			prefixes = append(prefixes, netip.PrefixFrom(ip, ip.BitLen()))
+		}
+	}
+
+	return func(addr string) bool {
+		ip, err := netip.ParseAddr(addr)
+		if err != nil {
+			return false
+		}
+		ip = ip.Unmap()
+		for _, p := range prefixes {
+			if p.Contains(ip) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+func actionLogToPb(l domain.ActionLog) *favorite.ActionLog {
+	return &favorite.ActionLog{
+		UserId:     l.UserId,
+		Biz:        l.Biz,
+		BizId:      l.BizId,
+		ActionType: l.ActionType,
+		CallerId:   l.CallerId,
+		ClientIp:   l.ClientIp,
+		TraceId:    l.TraceId,
+		Ctime:      l.Ctime,
+	}
+}
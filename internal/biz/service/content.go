@@ -0,0 +1,24 @@
+package service
+
+import (
+	"context"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/crazyfrankie/favorite/api/rpc_gen/favorite"
+)
+
+// PurgeContent 内容删除后清理其计数、点赞用户及所有用户点赞记录中的该内容
+func (f *FavoriteServer) PurgeContent(ctx context.Context, req *favorite.PurgeContentRequest) (*favorite.PurgeContentResponse, error) {
+	if req.GetBiz() == "" || req.GetBizId() <= 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid content: %s:%d", req.GetBiz(), req.GetBizId())
+	}
+
+	purged, err := f.repo.PurgeContent(ctx, req.GetBiz(), req.GetBizId())
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to purge content: %v", err)
+	}
+
+	return &favorite.PurgeContentResponse{Purged: purged}, nil
+}
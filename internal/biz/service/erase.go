@@ -0,0 +1,99 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/crazyfrankie/favorite/api/rpc_gen/favorite"
+	"github.com/crazyfrankie/favorite/internal/biz/domain"
+	"github.com/crazyfrankie/favorite/internal/biz/repository"
+	"github.com/crazyfrankie/favorite/pkg/auth"
+)
+
+// EraseUserFavorites 用户注销时擦除其全部点赞, 任务在后台执行
+func (f *FavoriteServer) EraseUserFavorites(ctx context.Context, req *favorite.EraseUserFavoritesRequest) (*favorite.EraseUserFavoritesResponse, error) {
+	if req.GetUserId() <= 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid user id: %d", req.GetUserId())
+	}
+
+	task, err := f.repo.CreateEraseTask(ctx, req.GetUserId())
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to create erase task: %v", err)
+	}
+	// 任务由执行者在数据库中领取, 与定时续跑并发时只有一方会执行
+	go f.eraseUser(context.Background(), req.GetUserId())
+
+	return &favorite.EraseUserFavoritesResponse{Task: eraseTaskToPb(task)}, nil
+}
+
+// GetEraseStatus 查询擦除任务状态, 仅用户本人和管理员可查询
+func (f *FavoriteServer) GetEraseStatus(ctx context.Context, req *favorite.GetEraseStatusRequest) (*favorite.GetEraseStatusResponse, error) {
+	if caller, ok := auth.CallerFromContext(ctx); (!ok || caller != req.GetUserId()) && !auth.HasRole(ctx, auth.RoleAdmin) {
+		return nil, status.Errorf(codes.PermissionDenied, "can only query own erase task")
+	}
+
+	task, err := f.repo.GetEraseTask(ctx, req.GetUserId())
+	if err != nil {
+		if errors.Is(err, repository.ErrTaskNotFound) {
+			return nil, status.Errorf(codes.NotFound, "erase task not found")
+		}
+		return nil, status.Errorf(codes.Internal, "failed to get erase task: %v", err)
+	}
+
+	return &favorite.GetEraseStatusResponse{Task: eraseTaskToPb(task)}, nil
+}
+
+// ResumeEraseTasks 继续执行中断或失败的擦除任务, 由定时任务调用; 逐个同步执行, 超时未完成的任务下次从断点继续
+func (f *FavoriteServer) ResumeEraseTasks(ctx context.Context) error {
+	uids, err := f.repo.ListUnfinishedEraseTasks(ctx, 100)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, uid := range uids {
+		if ctx.Err() != nil {
+			break
+		}
+		if err := f.eraseUser(ctx, uid); err != nil {
+			errs = append(errs, fmt.Errorf("uid %d: %w", uid, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// eraseUser 执行擦除任务, 已被其他执行者领取时直接返回;
+// 风控记录先于任务完成删除, 删除失败时任务保持未完成, 由定时任务重试
+func (f *FavoriteServer) eraseUser(ctx context.Context, uid int64) error {
+	if err := f.abuse.EraseUser(ctx, uid); err != nil {
+		zap.L().Error("erase user anti-abuse records failed", zap.Int64("uid", uid), zap.Error(err))
+		return err
+	}
+
+	err := f.repo.EraseUserFavorites(ctx, uid)
+	if errors.Is(err, repository.ErrTaskClaimed) {
+		return nil
+	}
+	if err != nil {
+		zap.L().Error("erase user favorites failed", zap.Int64("uid", uid), zap.Error(err))
+	}
+
+	return err
+}
+
+func eraseTaskToPb(t domain.EraseTask) *favorite.EraseTask {
+	return &favorite.EraseTask{
+		UserId:     t.UserId,
+		Status:     int32(t.Status),
+		Processed:  t.Processed,
+		Error:      t.Error,
+		Ctime:      t.Ctime,
+		FinishedAt: t.FinishedAt,
+	}
+}
@@ -0,0 +1,33 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/crazyfrankie/favorite/api/rpc_gen/favorite"
+	"github.com/crazyfrankie/favorite/pkg/auth"
+)
+
+func TestGetEraseStatusPermission(t *testing.T) {
+	f := &FavoriteServer{}
+
+	// 是否注销属于个人信息, 其他用户和服务都不能查询
+	tests := []struct {
+		name string
+		ctx  context.Context
+	}{
+		{name: "anonymous", ctx: context.Background()},
+		{name: "other user", ctx: auth.WithCaller(context.Background(), auth.Identity{UserId: 2})},
+		{name: "service", ctx: auth.WithCaller(context.Background(), auth.Identity{Role: auth.RoleService})},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := f.GetEraseStatus(tc.ctx, &favorite.GetEraseStatusRequest{UserId: 1})
+			assert.Equal(t, codes.PermissionDenied, status.Code(err))
+		})
+	}
+}
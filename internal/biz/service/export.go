@@ -0,0 +1,114 @@
+package service
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"strconv"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/crazyfrankie/favorite/api/rpc_gen/favorite"
+	"github.com/crazyfrankie/favorite/internal/biz/domain"
+	"github.com/crazyfrankie/favorite/pkg/auth"
+	"github.com/crazyfrankie/favorite/pkg/constants"
+)
+
+// ExportUserFavorites 分批流式导出用户的全部点赞, 只允许本人导出
+// Send 在客户端消费跟不上时会阻塞, 此时不会继续读取下一批, 以此实现背压
+func (f *FavoriteServer) ExportUserFavorites(req *favorite.ExportUserFavoritesRequest, stream grpc.ServerStreamingServer[favorite.ExportUserFavoritesResponse]) error {
+	ctx := stream.Context()
+
+	format := req.GetFormat()
+	if format != constants.ExportFormatProto && format != constants.ExportFormatJSONL && format != constants.ExportFormatCSV {
+		return status.Errorf(codes.InvalidArgument, "invalid export format: %d", format)
+	}
+	if caller, ok := auth.CallerFromContext(ctx); !ok || caller != req.GetUserId() {
+		return status.Errorf(codes.PermissionDenied, "can only export own favorites")
+	}
+
+	var (
+		cursor    int64
+		batchSize = 500
+		first     = true
+	)
+	for {
+		if err := ctx.Err(); err != nil {
+			return status.FromContextError(err).Err()
+		}
+
+		favs, next, err := f.repo.UserFavoritesPage(ctx, req.GetUserId(), cursor, batchSize)
+		if err != nil {
+			return status.Errorf(codes.Internal, "failed to export favorites: %v", err)
+		}
+
+		if len(favs) > 0 || first {
+			resp, err := encodeExport(format, favs, first)
+			if err != nil {
+				return status.Errorf(codes.Internal, "failed to encode favorites: %v", err)
+			}
+			if err := stream.Send(resp); err != nil {
+				return err
+			}
+			first = false
+		}
+
+		if next == 0 {
+			return nil
+		}
+		cursor = next
+	}
+}
+
+func encodeExport(format int32, favs []domain.UserFavorite, withHeader bool) (*favorite.ExportUserFavoritesResponse, error) {
+	switch format {
+	case constants.ExportFormatJSONL:
+		var buf bytes.Buffer
+		enc := json.NewEncoder(&buf)
+		for _, v := range favs {
+			if err := enc.Encode(exportRecord{Biz: v.Biz, BizId: v.BizId, Ctime: v.Ctime, Utime: v.Utime}); err != nil {
+				return nil, err
+			}
+		}
+		return &favorite.ExportUserFavoritesResponse{Data: buf.Bytes()}, nil
+	case constants.ExportFormatCSV:
+		var buf bytes.Buffer
+		w := csv.NewWriter(&buf)
+		if withHeader {
+			_ = w.Write([]string{"biz", "biz_id", "ctime", "utime"})
+		}
+		for _, v := range favs {
+			_ = w.Write([]string{
+				v.Biz,
+				strconv.FormatInt(v.BizId, 10),
+				strconv.FormatInt(v.Ctime, 10),
+				strconv.FormatInt(v.Utime, 10),
+			})
+		}
+		w.Flush()
+		if err := w.Error(); err != nil {
+			return nil, err
+		}
+		return &favorite.ExportUserFavoritesResponse{Data: buf.Bytes()}, nil
+	default:
+		res := make([]*favorite.ExportedFavorite, 0, len(favs))
+		for _, v := range favs {
+			res = append(res, &favorite.ExportedFavorite{
+				Biz:   v.Biz,
+				BizId: v.BizId,
+				Ctime: v.Ctime,
+				Utime: v.Utime,
+			})
+		}
+		return &favorite.ExportUserFavoritesResponse{Favorites: res}, nil
+	}
+}
+
+type exportRecord struct {
+	Biz   string `json:"biz"`
+	BizId int64  `json:"biz_id"`
+	Ctime int64  `json:"ctime"`
+	Utime int64  `json:"utime"`
+}
@@ -0,0 +1,91 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/crazyfrankie/favorite/api/rpc_gen/favorite"
+	"github.com/crazyfrankie/favorite/internal/biz/domain"
+	"github.com/crazyfrankie/favorite/pkg/constants"
+)
+
+const (
+	// 单次查询最多返回的点数
+	maxHistoryPoints = 2000
+	// 小时快照保留时长, 需大于汇总任务的周期
+	hourlyHistoryRetention = 30 * 24 * time.Hour
+	// 天快照保留时长
+	dailyHistoryRetention = 2 * 365 * 24 * time.Hour
+)
+
+// FavoriteCountHistory 获取内容点赞数的时间序列, from/to 为秒级时间戳, 按粒度对齐后左闭右开
+func (f *FavoriteServer) FavoriteCountHistory(ctx context.Context, req *favorite.FavoriteCountHistoryRequest) (*favorite.FavoriteCountHistoryResponse, error) {
+	var step int64
+	switch req.GetGranularity() {
+	case constants.HistoryGranularityHour:
+		step = int64(time.Hour / time.Second)
+	case constants.HistoryGranularityDay:
+		step = int64(24 * time.Hour / time.Second)
+	default:
+		return nil, status.Errorf(codes.InvalidArgument, "invalid granularity: %d", req.GetGranularity())
+	}
+
+	from := req.GetFrom() - req.GetFrom()%step
+	to := req.GetTo()
+	if to <= 0 {
+		to = time.Now().Unix()
+	}
+	if from >= to {
+		return nil, status.Errorf(codes.InvalidArgument, "from must be earlier than to")
+	}
+	if (to-from+step-1)/step > maxHistoryPoints {
+		return nil, status.Errorf(codes.InvalidArgument, "too many points, max %d", maxHistoryPoints)
+	}
+
+	base, snaps, err := f.repo.CountHistory(ctx, req.GetBiz(), req.GetBizId(), uint8(req.GetGranularity()), from, to)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get favorite count history: %v", err)
+	}
+
+	return &favorite.FavoriteCountHistoryResponse{Points: fillHistory(base, snaps, from, to, step)}, nil
+}
+
+// fillHistory 按 step 生成 [from, to) 内的每个点, 快照只在变化时记录, 没有快照的时间段沿用前一个值
+func fillHistory(base int64, snaps []domain.CountPoint, from, to, step int64) []*favorite.CountPoint {
+	points := make([]*favorite.CountPoint, 0, (to-from+step-1)/step)
+	count, i := base, 0
+	for t := from; t < to; t += step {
+		for i < len(snaps) && snaps[i].Time <= t {
+			count = snaps[i].Count
+			i++
+		}
+		points = append(points, &favorite.CountPoint{Time: t, Count: count})
+	}
+
+	return points
+}
+
+// SyncFavoriteCounts 将缓存中的点赞数同步到数据库并记录快照, threshold 见 FavoriteRepo.SyncFavoritesCount
+func (f *FavoriteServer) SyncFavoriteCounts(ctx context.Context, threshold int64) error {
+	return f.repo.SyncFavoritesCount(ctx, threshold)
+}
+
+// MaintainCountHistory 将前两天的小时快照汇总为天快照, 并清理过期快照
+// 汇总是幂等的, 重复汇总前一天可以覆盖上次运行后才同步的快照
+func (f *FavoriteServer) MaintainCountHistory(ctx context.Context) error {
+	now := time.Now()
+	today := now.Truncate(24 * time.Hour)
+	if err := f.repo.RollupCountHistory(ctx, today.AddDate(0, 0, -2).Unix(), today.Unix()); err != nil {
+		return err
+	}
+
+	if _, err := f.repo.DeleteCountHistory(ctx, constants.HistoryGranularityHour, now.Add(-hourlyHistoryRetention).Unix()); err != nil {
+		return err
+	}
+	_, err := f.repo.DeleteCountHistory(ctx, constants.HistoryGranularityDay, now.Add(-dailyHistoryRetention).Unix())
+
+	return err
+}
@@ -0,0 +1,62 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/crazyfrankie/favorite/api/rpc_gen/favorite"
+	"github.com/crazyfrankie/favorite/internal/biz/domain"
+)
+
+func TestFillHistory(t *testing.T) {
+	const step = 3600
+
+	tests := []struct {
+		name  string
+		base  int64
+		snaps []domain.CountPoint
+		want  []int64
+	}{
+		{
+			name: "no snapshots",
+			base: 5,
+			want: []int64{5, 5, 5, 5},
+		},
+		{
+			name:  "gaps keep previous value",
+			base:  1,
+			snaps: []domain.CountPoint{{Time: step, Count: 3}, {Time: 3 * step, Count: 2}},
+			want:  []int64{1, 3, 3, 2},
+		},
+		{
+			name:  "snapshot at from overrides base",
+			base:  1,
+			snaps: []domain.CountPoint{{Time: 0, Count: 7}},
+			want:  []int64{7, 7, 7, 7},
+		},
+		{
+			// 天粒度的点由小时快照补齐时时间戳可能不对齐, 归入之后的第一个点
+			name:  "unaligned snapshot",
+			snaps: []domain.CountPoint{{Time: step + 1, Count: 4}},
+			want:  []int64{0, 0, 4, 4},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			points := fillHistory(tc.base, tc.snaps, 0, 4*step, step)
+
+			want := make([]*favorite.CountPoint, 0, len(tc.want))
+			for i, c := range tc.want {
+				want = append(want, &favorite.CountPoint{Time: int64(i) * step, Count: c})
+			}
+			assert.Equal(t, want, points)
+		})
+	}
+}
+
+func TestFillHistoryPartialStep(t *testing.T) {
+	// to 不对齐时最后一个点仍然输出
+	points := fillHistory(2, nil, 0, 3600+1, 3600)
+	assert.Len(t, points, 2)
+}
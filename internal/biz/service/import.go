@@ -0,0 +1,164 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/crazyfrankie/favorite/api/rpc_gen/favorite"
+	"github.com/crazyfrankie/favorite/internal/biz/domain"
+	"github.com/crazyfrankie/favorite/internal/biz/repository"
+)
+
+// Importer 批量导入历史点赞, ImportFavorites 接口与离线导入命令共用
+type Importer struct {
+	repo         *repository.FavoriteRepo
+	batchSize    int
+	rebuildCache bool
+
+	// seen 只对当前批次去重, 跨批次的重复由数据库唯一索引忽略并计入 Duplicates
+	seen    map[string]struct{}
+	touched map[string]map[int64]struct{}
+	buf     []domain.ImportRecord
+	batch   int64
+	result  domain.ImportResult
+}
+
+func NewImporter(repo *repository.FavoriteRepo, batchSize int, rebuildCache bool) *Importer {
+	if batchSize <= 0 {
+		batchSize = 500
+	}
+
+	return &Importer{
+		repo:         repo,
+		batchSize:    batchSize,
+		rebuildCache: rebuildCache,
+		seen:         make(map[string]struct{}),
+		touched:      make(map[string]map[int64]struct{}),
+	}
+}
+
+// Add 校验并在批次内去重后加入缓冲区, 攒满一批时写入
+func (i *Importer) Add(ctx context.Context, rec domain.ImportRecord) {
+	i.result.Received++
+	if rec.UserId <= 0 || rec.Biz == "" || rec.BizId <= 0 {
+		i.result.Invalid++
+		return
+	}
+	if rec.LikedAt <= 0 {
+		rec.LikedAt = time.Now().Unix()
+	}
+
+	key := fmt.Sprintf("%d:%s:%d", rec.UserId, rec.Biz, rec.BizId)
+	if _, ok := i.seen[key]; ok {
+		i.result.Duplicates++
+		return
+	}
+	i.seen[key] = struct{}{}
+
+	i.buf = append(i.buf, rec)
+	if len(i.buf) >= i.batchSize {
+		i.flush(ctx)
+	}
+}
+
+// Close 写入剩余数据并重新计算受影响内容的点赞数, 无论是否重建缓存都会同步缓存计数
+func (i *Importer) Close(ctx context.Context) domain.ImportResult {
+	i.flush(ctx)
+
+	for biz, ids := range i.touched {
+		bizIds := make([]int64, 0, len(ids))
+		for id := range ids {
+			bizIds = append(bizIds, id)
+		}
+
+		for start := 0; start < len(bizIds); start += i.batchSize {
+			end := min(start+i.batchSize, len(bizIds))
+			if err := i.repo.RecountFavorites(ctx, biz, bizIds[start:end]); err != nil {
+				i.result.Errors = append(i.result.Errors, domain.ImportBatchError{
+					Batch: -1,
+					Err:   fmt.Sprintf("recount %s: %v", biz, err),
+				})
+			}
+		}
+	}
+
+	return i.result
+}
+
+func (i *Importer) flush(ctx context.Context) {
+	if len(i.buf) == 0 {
+		return
+	}
+	i.batch++
+
+	imported, err := i.repo.ImportFavorites(ctx, i.buf, i.rebuildCache)
+	i.result.Imported += imported
+	if err != nil {
+		i.result.Errors = append(i.result.Errors, domain.ImportBatchError{Batch: i.batch, Err: err.Error()})
+	}
+	// 数据库已存在的记录视为重复
+	if err == nil {
+		i.result.Duplicates += int64(len(i.buf)) - imported
+	}
+
+	for _, rec := range i.buf {
+		if i.touched[rec.Biz] == nil {
+			i.touched[rec.Biz] = make(map[int64]struct{})
+		}
+		i.touched[rec.Biz][rec.BizId] = struct{}{}
+	}
+	i.buf = i.buf[:0]
+	clear(i.seen)
+}
+
+// ImportFavorites 客户端流式导入历史点赞, rebuild_cache 以第一条消息为准
+func (f *FavoriteServer) ImportFavorites(stream grpc.ClientStreamingServer[favorite.ImportFavoritesRequest, favorite.ImportFavoritesResponse]) error {
+	ctx := stream.Context()
+
+	var importer *Importer
+	for {
+		req, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return err
+		}
+
+		if importer == nil {
+			importer = NewImporter(f.repo, 500, req.GetRebuildCache())
+		}
+		for _, r := range req.GetRecords() {
+			importer.Add(ctx, domain.ImportRecord{
+				UserId:  r.GetUserId(),
+				Biz:     r.GetBiz(),
+				BizId:   r.GetBizId(),
+				LikedAt: r.GetLikedAt(),
+			})
+		}
+	}
+	if importer == nil {
+		return status.Errorf(codes.InvalidArgument, "no records received")
+	}
+
+	res := importer.Close(ctx)
+	errs := make([]*favorite.ImportBatchError, 0, len(res.Errors))
+	for _, e := range res.Errors {
+		errs = append(errs, &favorite.ImportBatchError{Batch: e.Batch, Error: e.Err})
+	}
+
+	return stream.SendAndClose(&favorite.ImportFavoritesResponse{
+		Received:   res.Received,
+		Imported:   res.Imported,
+		Duplicates: res.Duplicates,
+		Invalid:    res.Invalid,
+		Errors:     errs,
+	})
+}
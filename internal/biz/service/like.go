@@ -3,23 +3,39 @@ package service
 import (
 	"context"
 	"errors"
+	"time"
+
+	"github.com/crazyfrankie/favorite/internal/biz/antiabuse"
+	"github.com/crazyfrankie/favorite/internal/biz/domain"
+	"github.com/crazyfrankie/favorite/internal/biz/events"
+	"github.com/crazyfrankie/favorite/internal/biz/metrics"
 	"github.com/crazyfrankie/favorite/internal/biz/repository"
+	"github.com/crazyfrankie/favorite/internal/biz/social"
 
+	"go.uber.org/zap"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 
 	"github.com/crazyfrankie/favorite/api/rpc_gen/favorite"
+	"github.com/crazyfrankie/favorite/pkg/auth"
 	"github.com/crazyfrankie/favorite/pkg/constants"
 )
 
+// 批量接口单次允许的最大条数
+const maxBatchSize = 500
+
 type FavoriteServer struct {
-	repo *repository.FavoriteRepo
+	repo  *repository.FavoriteRepo
+	abuse *antiabuse.Pipeline
+	graph social.Graph
+	// 点赞数变化的订阅分发
+	counts *events.CountHub
 
 	favorite.UnimplementedFavoriteServiceServer
 }
 
-func NewFavoriteServer(repo *repository.FavoriteRepo) *FavoriteServer {
-	return &FavoriteServer{repo: repo}
+func NewFavoriteServer(repo *repository.FavoriteRepo, abuse *antiabuse.Pipeline, graph social.Graph, counts *events.CountHub) *FavoriteServer {
+	return &FavoriteServer{repo: repo, abuse: abuse, graph: graph, counts: counts}
 }
 
 func (f *FavoriteServer) FavoriteAction(ctx context.Context, req *favorite.FavoriteActionRequest) (*favorite.FavoriteActionResponse, error) {
@@ -28,6 +44,9 @@ func (f *FavoriteServer) FavoriteAction(ctx context.Context, req *favorite.Favor
 	if action != constants.FavoriteActionType && action != constants.UnFavoriteActionType {
 		return nil, status.Errorf(codes.InvalidArgument, "invalid action type: %d", action)
 	}
+	if caller, ok := auth.CallerFromContext(ctx); !ok || caller != req.GetUserId() {
+		return nil, status.Errorf(codes.PermissionDenied, "can only like as yourself")
+	}
 
 	// 调用业务方查询内容是否存在（示例代码）
 	// video, err := f.videoClient.GetVideoExist(ctx, &video.GetVideoExistRequest{ id: req.GetBizId() })
@@ -35,13 +54,52 @@ func (f *FavoriteServer) FavoriteAction(ctx context.Context, req *favorite.Favor
 
 	userID, bizID, biz := req.GetUserId(), req.GetBizId(), req.GetBiz()
 
+	// 反作弊检测, 检测失败时不影响正常点赞
+	now := time.Now()
+	id, _ := auth.IdentityFromContext(ctx)
+	verdict, err := f.abuse.Evaluate(ctx, antiabuse.Action{
+		UserId:     userID,
+		Biz:        biz,
+		BizId:      bizID,
+		ActionType: action,
+		Time:       now,
+		SignupAt:   id.SignupAt,
+	})
+	if err != nil {
+		zap.L().Error("anti-abuse evaluate failed", zap.Error(err))
+	}
+
 	if action == constants.FavoriteActionType {
+		purged, err := f.repo.IsContentPurged(ctx, biz, bizID)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to check content: %v", err)
+		}
+		if purged {
+			return nil, status.Errorf(codes.NotFound, "content not found")
+		}
+	}
+
+	if action == constants.FavoriteActionType && verdict.Suspicious {
+		// 可疑点赞静默接受: 对用户可见, 但不计入公开计数
+		if err := f.repo.CreateShadowFavorite(ctx, domain.FlaggedFavorite{
+			UserId:    userID,
+			Biz:       biz,
+			BizId:     bizID,
+			Score:     verdict.Score,
+			Reasons:   verdict.Reasons,
+			FlaggedAt: now.Unix(),
+		}); err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to create favorite: %v", err)
+		}
+		metrics.Actions.WithLabelValues(metrics.BizLabel(biz), "shadow_like").Inc()
+	} else if action == constants.FavoriteActionType {
 		if err := f.repo.CreateFavorite(ctx, biz, bizID, userID); err != nil {
 			if errors.Is(err, repository.ErrAlreadyExists) {
 				return nil, status.Errorf(codes.AlreadyExists, "favorite already exists")
 			}
 			return nil, status.Errorf(codes.Internal, "failed to create favorite: %v", err)
 		}
+		metrics.Actions.WithLabelValues(metrics.BizLabel(biz), "like").Inc()
 	} else {
 		// 直接尝试删除点赞（内部保证幂等）
 		if err := f.repo.DeleteFavorite(ctx, biz, bizID, userID); err != nil {
@@ -50,13 +108,24 @@ func (f *FavoriteServer) FavoriteAction(ctx context.Context, req *favorite.Favor
 			}
 			return nil, status.Errorf(codes.Internal, "failed to delete favorite: %v", err)
 		}
+		metrics.Actions.WithLabelValues(metrics.BizLabel(biz), "unlike").Inc()
 	}
 
+	f.recordAction(ctx, req, now)
+
 	return &favorite.FavoriteActionResponse{}, nil
 }
 
 // FavoriteList 获取用户的点赞列表
 func (f *FavoriteServer) FavoriteList(ctx context.Context, req *favorite.FavoriteListRequest) (*favorite.FavoriteListResponse, error) {
+	visible, err := f.visibleUsers(ctx, []int64{req.GetUserId()})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get favorite privacy: %v", err)
+	}
+	if !visible[req.GetUserId()] {
+		return nil, status.Errorf(codes.PermissionDenied, "favorite list is not visible")
+	}
+
 	res, err := f.repo.UserFavoriteElements(ctx, req.GetUserId())
 	if err != nil {
 		return nil, status.Errorf(codes.Internal, "failed to get favorite list: %v", err)
@@ -82,11 +151,31 @@ func (f *FavoriteServer) BizFavoriteUser(ctx context.Context, req *favorite.BizF
 		return nil, status.Errorf(codes.Internal, "failed to get favorite user: %v", err)
 	}
 
-	return &favorite.BizFavoriteUserResponse{UserId: res}, nil
+	// 过滤掉对调用方隐藏点赞的用户
+	visible, err := f.visibleUsers(ctx, res)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get favorite privacy: %v", err)
+	}
+	users := make([]int64, 0, len(res))
+	for _, uid := range res {
+		if visible[uid] {
+			users = append(users, uid)
+		}
+	}
+
+	return &favorite.BizFavoriteUserResponse{UserId: users}, nil
 }
 
-// IsFavorite 获取用户是否点赞
+// IsFavorite 获取用户是否点赞, 对调用方隐藏点赞的用户始终返回未点赞
 func (f *FavoriteServer) IsFavorite(ctx context.Context, req *favorite.IsFavoriteRequest) (*favorite.IsFavoriteResponse, error) {
+	visible, err := f.visibleUsers(ctx, []int64{req.GetUserId()})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get favorite privacy: %v", err)
+	}
+	if !visible[req.GetUserId()] {
+		return &favorite.IsFavoriteResponse{}, nil
+	}
+
 	fav, err := f.repo.IsUserFavorite(ctx, req.GetBiz(), req.GetUserId(), req.GetBizId())
 	if err != nil {
 		return nil, status.Errorf(codes.Internal, "failed to get is favorite: %v", err)
@@ -95,6 +184,64 @@ func (f *FavoriteServer) IsFavorite(ctx context.Context, req *favorite.IsFavorit
 	return &favorite.IsFavoriteResponse{Favorite: fav}, nil
 }
 
+// BatchIsFavorite 批量获取用户是否点赞, 对调用方隐藏点赞的用户始终返回未点赞
+func (f *FavoriteServer) BatchIsFavorite(ctx context.Context, req *favorite.BatchIsFavoriteRequest) (*favorite.BatchIsFavoriteResponse, error) {
+	if len(req.GetItems()) > maxBatchSize {
+		return nil, status.Errorf(codes.InvalidArgument, "too many items: %d", len(req.GetItems()))
+	}
+	if len(req.GetItems()) == 0 {
+		return &favorite.BatchIsFavoriteResponse{}, nil
+	}
+
+	favs := make([]domain.UserFavorite, 0, len(req.GetItems()))
+	for _, v := range req.GetItems() {
+		favs = append(favs, domain.UserFavorite{UserId: v.GetUserId(), Biz: v.GetBiz(), BizId: v.GetBizId()})
+	}
+	res, err := f.repo.BatchIsUserFavorite(ctx, favs)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get is favorite: %v", err)
+	}
+
+	seen := make(map[int64]bool, len(favs))
+	uids := make([]int64, 0, len(favs))
+	for _, v := range favs {
+		if !seen[v.UserId] {
+			seen[v.UserId] = true
+			uids = append(uids, v.UserId)
+		}
+	}
+	visible, err := f.visibleUsers(ctx, uids)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get favorite privacy: %v", err)
+	}
+	for i, v := range favs {
+		res[i] = res[i] && visible[v.UserId]
+	}
+
+	return &favorite.BatchIsFavoriteResponse{Favorites: res}, nil
+}
+
+// BatchFavoriteCount 批量获取内容的点赞数
+func (f *FavoriteServer) BatchFavoriteCount(ctx context.Context, req *favorite.BatchFavoriteCountRequest) (*favorite.BatchFavoriteCountResponse, error) {
+	if len(req.GetItems()) > maxBatchSize {
+		return nil, status.Errorf(codes.InvalidArgument, "too many items: %d", len(req.GetItems()))
+	}
+	if len(req.GetItems()) == 0 {
+		return &favorite.BatchFavoriteCountResponse{}, nil
+	}
+
+	contents := make([]domain.FavoriteCount, 0, len(req.GetItems()))
+	for _, v := range req.GetItems() {
+		contents = append(contents, domain.FavoriteCount{Biz: v.GetBiz(), BizId: v.GetBizId()})
+	}
+	res, err := f.repo.BatchFavoriteCount(ctx, contents)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get favorite count: %v", err)
+	}
+
+	return &favorite.BatchFavoriteCountResponse{Counts: res}, nil
+}
+
 // UserFavoriteCount 获取用户的点赞总数
 func (f *FavoriteServer) UserFavoriteCount(ctx context.Context, req *favorite.UserFavoriteCountRequest) (*favorite.UserFavoriteCountResponse, error) {
 	count, err := f.repo.UserFavoriteCount(ctx, req.GetUserId())
@@ -114,3 +261,74 @@ func (f *FavoriteServer) UserFavoritedCount(ctx context.Context, req *favorite.U
 
 	return &favorite.UserFavoritedCountResponse{Count: count}, nil
 }
+
+// ListFlaggedFavorites 审核: 获取被反作弊标记的点赞
+func (f *FavoriteServer) ListFlaggedFavorites(ctx context.Context, req *favorite.ListFlaggedFavoritesRequest) (*favorite.ListFlaggedFavoritesResponse, error) {
+	limit := req.GetLimit()
+	if limit <= 0 || limit > 100 {
+		limit = 20
+	}
+
+	res, total, err := f.repo.ListFlaggedFavorites(ctx, req.GetOffset(), limit)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to list flagged favorites: %v", err)
+	}
+
+	favorites := make([]*favorite.FlaggedFavorite, 0, len(res))
+	for _, v := range res {
+		favorites = append(favorites, &favorite.FlaggedFavorite{
+			UserId:    v.UserId,
+			Biz:       v.Biz,
+			BizId:     v.BizId,
+			Score:     v.Score,
+			Reasons:   v.Reasons,
+			FlaggedAt: v.FlaggedAt,
+		})
+	}
+
+	return &favorite.ListFlaggedFavoritesResponse{Favorites: favorites, Total: total}, nil
+}
+
+// PurgeFlaggedFavorites 审核: 清除被反作弊标记的点赞
+func (f *FavoriteServer) PurgeFlaggedFavorites(ctx context.Context, req *favorite.PurgeFlaggedFavoritesRequest) (*favorite.PurgeFlaggedFavoritesResponse, error) {
+	var purged int64
+	for _, v := range req.GetFavorites() {
+		ok, err := f.repo.PurgeFlaggedFavorite(ctx, v.GetBiz(), v.GetBizId(), v.GetUserId())
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to purge flagged favorite: %v", err)
+		}
+		if ok {
+			purged++
+		}
+	}
+
+	return &favorite.PurgeFlaggedFavoritesResponse{Purged: purged}, nil
+}
+
+// UnFavoriteHistory 获取用户的取消点赞历史, 便于撤销误操作, 只允许本人查看
+func (f *FavoriteServer) UnFavoriteHistory(ctx context.Context, req *favorite.UnFavoriteHistoryRequest) (*favorite.UnFavoriteHistoryResponse, error) {
+	if caller, ok := auth.CallerFromContext(ctx); !ok || caller != req.GetUserId() {
+		return nil, status.Errorf(codes.PermissionDenied, "can only view own unfavorite history")
+	}
+
+	limit := req.GetLimit()
+	if limit <= 0 || limit > 100 {
+		limit = 20
+	}
+
+	res, err := f.repo.UserUnFavorites(ctx, req.GetUserId(), req.GetOffset(), limit)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get unfavorite history: %v", err)
+	}
+
+	items := make([]*favorite.UnFavoriteItem, 0, len(res))
+	for _, v := range res {
+		items = append(items, &favorite.UnFavoriteItem{
+			Biz:       v.Biz,
+			BizId:     v.BizId,
+			UnlikedAt: v.UnlikedAt,
+		})
+	}
+
+	return &favorite.UnFavoriteHistoryResponse{Items: items}, nil
+}
@@ -0,0 +1,26 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/crazyfrankie/favorite/api/rpc_gen/favorite"
+	"github.com/crazyfrankie/favorite/pkg/auth"
+	"github.com/crazyfrankie/favorite/pkg/constants"
+)
+
+func TestFavoriteActionCallerMismatch(t *testing.T) {
+	f := &FavoriteServer{}
+	req := &favorite.FavoriteActionRequest{UserId: 2, Biz: "post", BizId: 1, ActionType: constants.FavoriteActionType}
+
+	_, err := f.FavoriteAction(context.Background(), req)
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+
+	ctx := auth.WithCaller(context.Background(), auth.Identity{UserId: 1})
+	_, err = f.FavoriteAction(ctx, req)
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+}
@@ -0,0 +1,33 @@
+package service
+
+import (
+	"context"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/crazyfrankie/favorite/api/rpc_gen/favorite"
+)
+
+// RegisterContentOwner 登记内容归属, 仅供内容服务在发布内容时调用, 调用方角色由 authInterceptor 校验
+func (f *FavoriteServer) RegisterContentOwner(ctx context.Context, req *favorite.RegisterContentOwnerRequest) (*favorite.RegisterContentOwnerResponse, error) {
+	if req.GetBiz() == "" || req.GetOwnerId() <= 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "biz and owner_id are required")
+	}
+
+	if err := f.repo.RegisterContentOwner(ctx, req.GetBiz(), req.GetBizId(), req.GetOwnerId()); err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to register content owner: %v", err)
+	}
+
+	return &favorite.RegisterContentOwnerResponse{}, nil
+}
+
+// CreatorReceivedCount 获取创作者在某个业务下收到的点赞总数
+func (f *FavoriteServer) CreatorReceivedCount(ctx context.Context, req *favorite.CreatorReceivedCountRequest) (*favorite.CreatorReceivedCountResponse, error) {
+	count, err := f.repo.CreatorReceivedCount(ctx, req.GetOwnerId(), req.GetBiz())
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get creator received count: %v", err)
+	}
+
+	return &favorite.CreatorReceivedCountResponse{Count: count}, nil
+}
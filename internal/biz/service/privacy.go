@@ -0,0 +1,72 @@
+package service
+
+import (
+	"context"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/crazyfrankie/favorite/api/rpc_gen/favorite"
+	"github.com/crazyfrankie/favorite/pkg/auth"
+	"github.com/crazyfrankie/favorite/pkg/constants"
+)
+
+// GetFavoritePrivacy 获取用户的点赞隐私设置
+func (f *FavoriteServer) GetFavoritePrivacy(ctx context.Context, req *favorite.GetFavoritePrivacyRequest) (*favorite.GetFavoritePrivacyResponse, error) {
+	res, err := f.repo.GetPrivacy(ctx, []int64{req.GetUserId()})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get favorite privacy: %v", err)
+	}
+
+	return &favorite.GetFavoritePrivacyResponse{Visibility: int32(res[req.GetUserId()])}, nil
+}
+
+// UpdateFavoritePrivacy 更新用户的点赞隐私设置, 只允许本人修改
+func (f *FavoriteServer) UpdateFavoritePrivacy(ctx context.Context, req *favorite.UpdateFavoritePrivacyRequest) (*favorite.UpdateFavoritePrivacyResponse, error) {
+	visibility := req.GetVisibility()
+	if visibility != constants.PrivacyPublic && visibility != constants.PrivacyFollowers && visibility != constants.PrivacyPrivate {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid visibility: %d", visibility)
+	}
+	if caller, ok := auth.CallerFromContext(ctx); !ok || caller != req.GetUserId() {
+		return nil, status.Errorf(codes.PermissionDenied, "can only update own privacy setting")
+	}
+
+	if err := f.repo.SetPrivacy(ctx, req.GetUserId(), uint8(visibility)); err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to update favorite privacy: %v", err)
+	}
+
+	return &favorite.UpdateFavoritePrivacyResponse{}, nil
+}
+
+// visibleUsers 根据隐私设置判断调用方能否看到这些用户的点赞
+func (f *FavoriteServer) visibleUsers(ctx context.Context, uids []int64) (map[int64]bool, error) {
+	privacies, err := f.repo.GetPrivacy(ctx, uids)
+	if err != nil {
+		return nil, err
+	}
+
+	caller, authed := auth.CallerFromContext(ctx)
+	res := make(map[int64]bool, len(uids))
+	for _, uid := range uids {
+		if authed && caller == uid {
+			res[uid] = true
+			continue
+		}
+
+		switch privacies[uid] {
+		case constants.PrivacyPublic:
+			res[uid] = true
+		case constants.PrivacyFollowers:
+			if !authed {
+				continue
+			}
+			ok, err := f.graph.IsFollower(ctx, caller, uid)
+			if err != nil {
+				return nil, err
+			}
+			res[uid] = ok
+		}
+	}
+
+	return res, nil
+}
@@ -0,0 +1,75 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/crazyfrankie/favorite/api/rpc_gen/favorite"
+	"github.com/crazyfrankie/favorite/internal/biz/repository"
+	"github.com/crazyfrankie/favorite/internal/biz/repository/cache"
+	"github.com/crazyfrankie/favorite/internal/biz/social"
+	"github.com/crazyfrankie/favorite/pkg/auth"
+	"github.com/crazyfrankie/favorite/pkg/constants"
+)
+
+// newPrivacyTestServer 用户 1 公开点赞, 用户 2 隐藏点赞, 两人都点赞了 post:1
+func newPrivacyTestServer(t *testing.T) *FavoriteServer {
+	t.Helper()
+
+	mr := miniredis.RunT(t)
+	cmd := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { _ = cmd.Close() })
+
+	ctx := context.Background()
+	c := cache.NewFavoriteCache(cmd)
+	require.NoError(t, c.SetPrivacy(ctx, map[int64]uint8{1: constants.PrivacyPublic, 2: constants.PrivacyPrivate}))
+	require.NoError(t, c.CreateFavorite(ctx, "post", 1, 1))
+	require.NoError(t, c.CreateFavorite(ctx, "post", 1, 2))
+
+	return NewFavoriteServer(repository.NewFavoriteRepo(c, nil, nil), nil, social.NewNoopGraph(), nil)
+}
+
+func TestIsFavoritePrivateUser(t *testing.T) {
+	f := newPrivacyTestServer(t)
+
+	tests := []struct {
+		name string
+		ctx  context.Context
+		uid  int64
+		want bool
+	}{
+		{name: "public", ctx: context.Background(), uid: 1, want: true},
+		{name: "private anonymous", ctx: context.Background(), uid: 2, want: false},
+		{name: "private other user", ctx: auth.WithCaller(context.Background(), auth.Identity{UserId: 1}), uid: 2, want: false},
+		{name: "private self", ctx: auth.WithCaller(context.Background(), auth.Identity{UserId: 2}), uid: 2, want: true},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			resp, err := f.IsFavorite(tc.ctx, &favorite.IsFavoriteRequest{Biz: "post", BizId: 1, UserId: tc.uid})
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, resp.GetFavorite())
+		})
+	}
+}
+
+func TestBatchIsFavoritePrivateUser(t *testing.T) {
+	f := newPrivacyTestServer(t)
+	req := &favorite.BatchIsFavoriteRequest{Items: []*favorite.IsFavoriteRequest{
+		{Biz: "post", BizId: 1, UserId: 1},
+		{Biz: "post", BizId: 1, UserId: 2},
+		{Biz: "post", BizId: 2, UserId: 1},
+	}}
+
+	resp, err := f.BatchIsFavorite(context.Background(), req)
+	require.NoError(t, err)
+	assert.Equal(t, []bool{true, false, false}, resp.GetFavorites())
+
+	resp, err = f.BatchIsFavorite(auth.WithCaller(context.Background(), auth.Identity{UserId: 2}), req)
+	require.NoError(t, err)
+	assert.Equal(t, []bool{true, true, false}, resp.GetFavorites())
+}
@@ -0,0 +1,144 @@
+package service
+
+import (
+	"context"
+	"math/rand/v2"
+	"slices"
+	"sort"
+	"time"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/crazyfrankie/favorite/api/rpc_gen/favorite"
+	"github.com/crazyfrankie/favorite/internal/biz/domain"
+	"github.com/crazyfrankie/favorite/internal/biz/repository"
+)
+
+const (
+	defaultRelatedK = 10
+	// 两个内容至少被这么多用户同时点赞才视为相关
+	relatedMinSupport = 2
+	// 点赞数超过该值的用户不参与计算, 避免刷量账号和重度用户主导结果
+	relatedMaxUserLikes = 1000
+	// 单个用户最多取这么多点赞两两组合, 超出时随机抽样, 限制单个用户产生的组合数
+	relatedSampleLikes = 100
+	// 单个内容最多统计的候选相关内容数, 达到上限后不再加入新的候选, 限制共现矩阵的内存占用
+	relatedMaxCandidates = 500
+)
+
+// RelatedContent 获取与内容相似度最高的 k 个内容
+func (f *FavoriteServer) RelatedContent(ctx context.Context, req *favorite.RelatedContentRequest) (*favorite.RelatedContentResponse, error) {
+	k := int(req.GetK())
+	if k <= 0 {
+		k = defaultRelatedK
+	}
+	k = min(k, repository.MaxRelatedItems)
+
+	res, err := f.repo.RelatedContent(ctx, req.GetBiz(), req.GetBizId(), k)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get related content: %v", err)
+	}
+
+	items := make([]*favorite.RelatedItem, 0, len(res))
+	for _, v := range res {
+		items = append(items, &favorite.RelatedItem{BizId: v.BizId, Score: v.Score})
+	}
+
+	return &favorite.RelatedContentResponse{Items: items}, nil
+}
+
+// ComputeRelatedContent 按业务计算每个内容的相关内容, 单个业务失败不影响其他业务
+func (f *FavoriteServer) ComputeRelatedContent(ctx context.Context) error {
+	bizTypes, err := f.repo.BizTypes(ctx)
+	if err != nil {
+		return err
+	}
+
+	var lastErr error
+	for _, biz := range bizTypes {
+		if err := f.computeRelated(ctx, biz); err != nil {
+			zap.L().Error("compute related content failed", zap.String("biz", biz), zap.Error(err))
+			lastErr = err
+		}
+	}
+
+	return lastErr
+}
+
+// computeRelated 统计内容两两被同时点赞的次数, 以 Jaccard 相似度 |A∩B| / |A∪B| 取 top-K
+func (f *FavoriteServer) computeRelated(ctx context.Context, biz string) error {
+	version := time.Now().UnixMilli()
+
+	likers := make(map[int64]int64)
+	co := make(map[int64]map[int64]int64)
+	addPair := func(a, b int64) {
+		others := co[a]
+		if others == nil {
+			others = make(map[int64]int64)
+			co[a] = others
+		}
+		if _, ok := others[b]; !ok && len(others) >= relatedMaxCandidates {
+			return
+		}
+		others[b]++
+	}
+	err := f.repo.ScanBizFavorites(ctx, biz, func(uid int64, bizIds []int64) error {
+		if len(bizIds) > relatedMaxUserLikes {
+			return nil
+		}
+		for _, a := range bizIds {
+			likers[a]++
+		}
+
+		sample := bizIds
+		if len(sample) > relatedSampleLikes {
+			sample = slices.Clone(bizIds)
+			rand.Shuffle(len(sample), func(i, j int) {
+				sample[i], sample[j] = sample[j], sample[i]
+			})
+			sample = sample[:relatedSampleLikes]
+		}
+		for i, a := range sample {
+			for _, b := range sample[i+1:] {
+				addPair(a, b)
+				addPair(b, a)
+			}
+		}
+		return ctx.Err()
+	})
+	if err != nil {
+		return err
+	}
+
+	related := make(map[int64][]domain.RelatedItem, len(co))
+	for a, others := range co {
+		items := make([]domain.RelatedItem, 0, len(others))
+		for b, both := range others {
+			if both < relatedMinSupport {
+				continue
+			}
+			items = append(items, domain.RelatedItem{
+				BizId: b,
+				Score: float64(both) / float64(likers[a]+likers[b]-both),
+			})
+		}
+		if len(items) == 0 {
+			continue
+		}
+
+		sort.Slice(items, func(i, j int) bool {
+			if items[i].Score != items[j].Score {
+				return items[i].Score > items[j].Score
+			}
+			return items[i].BizId < items[j].BizId
+		})
+		if len(items) > repository.MaxRelatedItems {
+			items = items[:repository.MaxRelatedItems]
+		}
+		related[a] = items
+	}
+
+	return f.repo.SaveRelatedContent(ctx, biz, related, version)
+}
@@ -0,0 +1,110 @@
+package service
+
+import (
+	"context"
+	"slices"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/crazyfrankie/favorite/api/rpc_gen/favorite"
+	"github.com/crazyfrankie/favorite/pkg/auth"
+)
+
+const (
+	defaultSocialLimit = 10
+	maxSocialLimit     = 100
+	// 调用方传入的好友 ID 上限, 更多的好友由社交关系服务提供
+	maxFriendIds = 5000
+)
+
+// FriendsFavorited 获取 viewer 的好友中点赞了该内容的用户及总数, 对 viewer 隐藏点赞的好友不计入
+func (f *FavoriteServer) FriendsFavorited(ctx context.Context, req *favorite.FriendsFavoritedRequest) (*favorite.FriendsFavoritedResponse, error) {
+	if caller, ok := auth.CallerFromContext(ctx); !ok || caller != req.GetViewerId() {
+		return nil, status.Errorf(codes.PermissionDenied, "can only query own friends")
+	}
+	if len(req.GetFriendIds()) > maxFriendIds {
+		return nil, status.Errorf(codes.InvalidArgument, "too many friend ids: %d > %d", len(req.GetFriendIds()), maxFriendIds)
+	}
+
+	limit := int(req.GetLimit())
+	if limit <= 0 {
+		limit = defaultSocialLimit
+	}
+	limit = min(limit, maxSocialLimit)
+
+	friends := req.GetFriendIds()
+	if len(friends) == 0 {
+		var err error
+		friends, err = f.graph.Friends(ctx, req.GetViewerId())
+		if err != nil {
+			return nil, status.Errorf(codes.Unavailable, "failed to get friends: %v", err)
+		}
+	}
+	if len(friends) == 0 {
+		return &favorite.FriendsFavoritedResponse{}, nil
+	}
+
+	likers, err := f.repo.FriendLikers(ctx, req.GetBiz(), req.GetBizId(), friends)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get friend likers: %v", err)
+	}
+
+	visible, err := f.visibleUsers(ctx, likers)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get favorite privacy: %v", err)
+	}
+	users := make([]int64, 0, len(likers))
+	for _, uid := range likers {
+		if visible[uid] {
+			users = append(users, uid)
+		}
+	}
+	slices.Sort(users)
+	users = slices.Compact(users)
+
+	total := int64(len(users))
+	if len(users) > limit {
+		users = users[:limit]
+	}
+
+	return &favorite.FriendsFavoritedResponse{UserIds: users, Total: total}, nil
+}
+
+// CommonFavorites 获取两个用户都点赞了的内容, 调用方需能看到两人的点赞
+func (f *FavoriteServer) CommonFavorites(ctx context.Context, req *favorite.CommonFavoritesRequest) (*favorite.CommonFavoritesResponse, error) {
+	userA, userB := req.GetUserA(), req.GetUserB()
+	if userA == userB {
+		return nil, status.Errorf(codes.InvalidArgument, "user_a and user_b must be different")
+	}
+
+	limit := int(req.GetLimit())
+	if limit <= 0 {
+		limit = defaultSocialLimit
+	}
+	limit = min(limit, maxSocialLimit)
+
+	visible, err := f.visibleUsers(ctx, []int64{userA, userB})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get favorite privacy: %v", err)
+	}
+	if !visible[userA] || !visible[userB] {
+		return nil, status.Errorf(codes.PermissionDenied, "favorites are not visible to caller")
+	}
+
+	res, err := f.repo.CommonFavorites(ctx, userA, userB, req.GetBiz(), limit)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get common favorites: %v", err)
+	}
+
+	items := make([]*favorite.CommonFavorite, 0, len(res))
+	for _, v := range res {
+		items = append(items, &favorite.CommonFavorite{
+			Biz:     v.Biz,
+			BizId:   v.BizId,
+			LikedAt: v.LikedAt,
+		})
+	}
+
+	return &favorite.CommonFavoritesResponse{Items: items}, nil
+}
@@ -0,0 +1,54 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/crazyfrankie/favorite/internal/biz/metrics"
+)
+
+const (
+	// 活跃点赞用户的统计窗口
+	activeLikersWindow = 15 * time.Minute
+	// 每次抽样检查计数偏差的内容数
+	driftSampleSize = 200
+)
+
+// CollectMetrics 采集需要查询存储才能得到的指标, 单项失败不影响其他指标
+func (f *FavoriteServer) CollectMetrics(ctx context.Context) error {
+	var errs []error
+
+	if n, err := f.repo.ActiveLikers(ctx, activeLikersWindow); err != nil {
+		errs = append(errs, err)
+	} else {
+		metrics.ActiveLikers.Set(float64(n))
+	}
+
+	if drift, err := f.repo.SampleCounterDrift(ctx, driftSampleSize); err != nil {
+		errs = append(errs, err)
+	} else {
+		byLabel := make(map[string]int64, len(drift))
+		for biz, v := range drift {
+			byLabel[metrics.BizLabel(biz)] += v
+		}
+		metrics.CounterDrift.Reset()
+		for label, v := range byLabel {
+			metrics.CounterDrift.WithLabelValues(label).Set(float64(v))
+		}
+	}
+
+	if last, err := f.repo.LastSynced(ctx); err != nil {
+		errs = append(errs, err)
+	} else if !last.IsZero() {
+		metrics.SyncLag.Set(time.Since(last).Seconds())
+	}
+
+	if n, err := f.repo.PendingEraseTasks(ctx); err != nil {
+		errs = append(errs, err)
+	} else {
+		metrics.QueueDepth.WithLabelValues("erase_tasks").Set(float64(n))
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,101 @@
+package service
+
+import (
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/crazyfrankie/favorite/api/rpc_gen/favorite"
+	"github.com/crazyfrankie/favorite/internal/biz/domain"
+	"github.com/crazyfrankie/favorite/internal/config"
+)
+
+const (
+	defaultWatchRate  = 5
+	defaultWatchItems = 100
+)
+
+// WatchFavoriteCount 推送内容点赞数的实时变化
+// 两次推送之间同一内容的多次变化合并为最新值, 每个订阅者每秒最多推送 max_rate 次
+func (f *FavoriteServer) WatchFavoriteCount(req *favorite.WatchFavoriteCountRequest, stream grpc.ServerStreamingServer[favorite.WatchFavoriteCountResponse]) error {
+	ctx := stream.Context()
+	conf := config.GetConf().Watch
+
+	maxItems := conf.MaxItems
+	if maxItems <= 0 {
+		maxItems = defaultWatchItems
+	}
+	if req.GetBiz() == "" || len(req.GetBizIds()) == 0 {
+		return status.Errorf(codes.InvalidArgument, "biz and biz_ids are required")
+	}
+	if len(req.GetBizIds()) > maxItems {
+		return status.Errorf(codes.InvalidArgument, "too many biz_ids: %d > %d", len(req.GetBizIds()), maxItems)
+	}
+
+	rate := conf.MaxRate
+	if rate <= 0 {
+		rate = defaultWatchRate
+	}
+	if r := int(req.GetMaxRate()); r > 0 && r < rate {
+		rate = r
+	}
+	interval := time.Second / time.Duration(rate)
+
+	// 先订阅再读取当前值, 避免两者之间的变化丢失
+	sub := f.counts.Subscribe(req.GetBiz(), req.GetBizIds())
+	defer sub.Close()
+
+	contents := make([]domain.FavoriteCount, 0, len(req.GetBizIds()))
+	for _, id := range req.GetBizIds() {
+		contents = append(contents, domain.FavoriteCount{Biz: req.GetBiz(), BizId: id})
+	}
+	counts, err := f.repo.BatchFavoriteCount(ctx, contents)
+	if err != nil {
+		return status.Errorf(codes.Internal, "failed to get favorite count: %v", err)
+	}
+	for i := range contents {
+		contents[i].Count = counts[i]
+	}
+	if err := stream.Send(countUpdates(contents)); err != nil {
+		return err
+	}
+
+	timer := time.NewTimer(interval)
+	defer timer.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-sub.Notify():
+		}
+
+		if changes := sub.Drain(); len(changes) > 0 {
+			if err := stream.Send(countUpdates(changes)); err != nil {
+				return err
+			}
+		}
+
+		// 限制推送频率, 期间的变化在下次推送时合并
+		timer.Reset(interval)
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-timer.C:
+		}
+	}
+}
+
+func countUpdates(counts []domain.FavoriteCount) *favorite.WatchFavoriteCountResponse {
+	updates := make([]*favorite.FavoriteCountUpdate, 0, len(counts))
+	for _, c := range counts {
+		updates = append(updates, &favorite.FavoriteCountUpdate{
+			Biz:   c.Biz,
+			BizId: c.BizId,
+			Count: c.Count,
+		})
+	}
+
+	return &favorite.WatchFavoriteCountResponse{Updates: updates}
+}
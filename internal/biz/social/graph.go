@@ -0,0 +1,26 @@
+package social
+
+import "context"
+
+// Graph 社交关系提供方, 由接入方根据自身关注体系实现
+type Graph interface {
+	// IsFollower follower 是否关注了 followee
+	IsFollower(ctx context.Context, follower, followee int64) (bool, error)
+	// Friends 获取用户的好友列表
+	Friends(ctx context.Context, uid int64) ([]int64, error)
+}
+
+// NoopGraph 未接入社交关系时的默认实现, 任何人都不是关注者, 也没有好友
+type NoopGraph struct{}
+
+func NewNoopGraph() Graph {
+	return NoopGraph{}
+}
+
+func (NoopGraph) IsFollower(ctx context.Context, follower, followee int64) (bool, error) {
+	return false, nil
+}
+
+func (NoopGraph) Friends(ctx context.Context, uid int64) ([]int64, error) {
+	return nil, nil
+}
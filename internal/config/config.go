@@ -3,29 +3,51 @@ package config
 import (
 	"fmt"
 	"github.com/joho/godotenv"
+	"log"
 	"os"
 	"path/filepath"
+	"slices"
 	"sync"
+	"sync/atomic"
+	"time"
 
+	"github.com/fsnotify/fsnotify"
 	"github.com/spf13/viper"
 )
 
 var (
 	once sync.Once
-	conf *Config
+	conf atomic.Pointer[Config]
+
+	watchOnce sync.Once
+	mu        sync.Mutex
+	listeners []func(*Config)
 )
 
 type Config struct {
-	Env    string
-	Server Server `yaml:"server"`
-	MySQL  MySQL  `yaml:"mysql"`
-	Redis  Redis  `yaml:"redis"`
-	JWT    JWT    `yaml:"jwt"`
-	ETCD   ETCD   `yaml:"etcd"`
+	Env       string
+	Server    Server    `yaml:"server"`
+	MySQL     MySQL     `yaml:"mysql"`
+	Redis     Redis     `yaml:"redis"`
+	JWT       JWT       `yaml:"jwt"`
+	ETCD      ETCD      `yaml:"etcd"`
+	Registry  Registry  `yaml:"registry"`
+	RateLimit RateLimit `yaml:"rateLimit"`
+	AntiAbuse AntiAbuse `yaml:"antiAbuse"`
+	Watch     Watch     `yaml:"watch"`
+	JobLock   JobLock   `yaml:"jobLock"`
+	Metrics   Metrics   `yaml:"metrics"`
+	// Jobs 定时任务配置, key 为任务名
+	Jobs map[string]Job `yaml:"jobs"`
 }
 
 type Server struct {
 	Port string `yaml:"port"`
+	// HTTP/JSON 网关的监听地址, 与 /metrics 等内部接口分开, 为空时使用 :9093
+	GatewayPort string `yaml:"gatewayPort"`
+	// 可信代理的 IP 或 CIDR, 只有来自这些地址的 x-forwarded-for 才会被采信, 为空时只使用连接对端地址;
+	// 内置网关经本机回环地址转发, 需记录其客户端地址时应包含 127.0.0.1
+	TrustedProxies []string `yaml:"trustedProxies"`
 }
 
 type MySQL struct {
@@ -40,15 +62,72 @@ type ETCD struct {
 	EndPoints string `yaml:"endPoints"`
 }
 
+type Registry struct {
+	// 注册到 etcd 的地址, 可只填 host, 为空时自动探测本机 IP
+	AdvertiseAddr string `yaml:"advertiseAddr"`
+	// 租约时长, 单位秒
+	TTL     int64  `yaml:"ttl"`
+	Version string `yaml:"version"`
+	Zone    string `yaml:"zone"`
+	Weight  int    `yaml:"weight"`
+}
+
 type JWT struct {
 	SecretKey string `yaml:"secretKey"`
 }
 
+type RateLimit struct {
+	Enabled bool `yaml:"enabled"`
+	// 按用户等级区分的单用户限流规则, key 为 token 中的 tier 声明, default 为兜底规则
+	Tiers map[string]Limit `yaml:"tiers"`
+	// 按业务区分的单内容限流规则, key 为 biz, default 为兜底规则
+	Biz map[string]Limit `yaml:"biz"`
+}
+
+type Limit struct {
+	Window time.Duration `yaml:"window"`
+	Rate   int           `yaml:"rate"`
+}
+
 func GetConf() *Config {
 	once.Do(func() {
 		initConf()
 	})
-	return conf
+	return conf.Load()
+}
+
+// OnChange 配置文件变更后以新配置回调 fn, 回调在 viper 的监听协程中执行
+func OnChange(fn func(*Config)) {
+	GetConf()
+
+	mu.Lock()
+	listeners = append(listeners, fn)
+	mu.Unlock()
+
+	watchOnce.Do(func() {
+		viper.OnConfigChange(func(e fsnotify.Event) {
+			reload()
+		})
+		viper.WatchConfig()
+	})
+}
+
+// reload 解析失败时保留旧配置
+func reload() {
+	c := new(Config)
+	if err := viper.Unmarshal(c); err != nil {
+		log.Printf("failed to reload config: %v", err)
+		return
+	}
+	c.Env = conf.Load().Env
+	conf.Store(c)
+
+	mu.Lock()
+	fns := slices.Clone(listeners)
+	mu.Unlock()
+	for _, fn := range fns {
+		fn(c)
+	}
 }
 
 func initConf() {
@@ -68,13 +147,14 @@ func initConf() {
 		panic(err)
 	}
 
-	conf = new(Config)
-	if err := viper.Unmarshal(conf); err != nil {
+	c := new(Config)
+	if err := viper.Unmarshal(c); err != nil {
 		panic(err)
 	}
 
-	conf.Env = env
-	fmt.Printf("%#v", conf)
+	c.Env = env
+	conf.Store(c)
+	fmt.Printf("%#v", c)
 }
 
 func getGoEnv() string {
@@ -85,3 +165,57 @@ func getGoEnv() string {
 
 	return "test"
 }
+
+type AntiAbuse struct {
+	Enabled bool `yaml:"enabled"`
+	// 各规则分数之和达到该值时视为可疑点赞
+	Threshold float64 `yaml:"threshold"`
+	Burst     Burst   `yaml:"burst"`
+	Cycle     Cycle   `yaml:"cycle"`
+}
+
+// Burst 新账号集中点赞检测
+type Burst struct {
+	Window   time.Duration `yaml:"window"`
+	FreshAge time.Duration `yaml:"freshAge"`
+	Limit    int64         `yaml:"limit"`
+}
+
+// Cycle 反复点赞/取消点赞检测
+type Cycle struct {
+	Window time.Duration `yaml:"window"`
+	Limit  int64         `yaml:"limit"`
+}
+
+// Watch 点赞数实时推送
+type Watch struct {
+	// 单个订阅者每秒最多推送次数
+	MaxRate int `yaml:"maxRate"`
+	// 单个订阅最多包含的内容数
+	MaxItems int `yaml:"maxItems"`
+}
+
+// Metrics 领域指标
+type Metrics struct {
+	// 作为指标 biz 标签取值的业务, 其余业务归为 other, 避免调用方传入的 biz 撑爆标签基数
+	Biz []string `yaml:"biz"`
+}
+
+// JobLock 定时任务分布式锁, 多实例部署时保证同一任务只在一个实例上执行
+type JobLock struct {
+	Enabled bool `yaml:"enabled"`
+	// 锁租约时长, 单位秒, 持有期间自动续约
+	TTL int `yaml:"ttl"`
+	// 任务结束后锁至少保留的时长, 避免各实例时钟偏差导致同一轮任务重复执行
+	MinHold time.Duration `yaml:"minHold"`
+}
+
+// Job 单个定时任务的配置, 未配置的任务及字段使用代码中的默认值
+type Job struct {
+	Disabled bool `yaml:"disabled"`
+	// cron 表达式, 精确到秒
+	Spec    string        `yaml:"spec"`
+	Timeout time.Duration `yaml:"timeout"`
+	// 仅部分任务使用
+	Threshold int64 `yaml:"threshold"`
+}
@@ -0,0 +1,17 @@
+package ioc
+
+import (
+	"github.com/crazyfrankie/favorite/internal/biz/events"
+	"github.com/crazyfrankie/favorite/internal/biz/service"
+	"github.com/crazyfrankie/favorite/job/scheduler"
+	"github.com/crazyfrankie/favorite/rpc"
+)
+
+type App struct {
+	Server   *rpc.Server
+	Favorite *service.FavoriteServer
+	Content  *events.ContentConsumer
+	Counts   *events.CountHub
+	Locker   scheduler.JobLocker
+	Pauses   scheduler.PauseStore
+}
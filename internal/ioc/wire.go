@@ -3,20 +3,29 @@
 package ioc
 
 import (
+	"context"
 	"fmt"
 	"os"
+	"time"
 
 	"github.com/google/wire"
 	"github.com/redis/go-redis/v9"
+	clientv3 "go.etcd.io/etcd/client/v3"
 	"gorm.io/driver/mysql"
 	"gorm.io/gorm"
 	"gorm.io/gorm/schema"
 
-	"github.com/crazyfrankie/favorite/internal/config"
+	"github.com/crazyfrankie/favorite/internal/biz/antiabuse"
+	"github.com/crazyfrankie/favorite/internal/biz/events"
 	"github.com/crazyfrankie/favorite/internal/biz/repository"
 	"github.com/crazyfrankie/favorite/internal/biz/repository/cache"
 	"github.com/crazyfrankie/favorite/internal/biz/repository/dao"
 	"github.com/crazyfrankie/favorite/internal/biz/service"
+	"github.com/crazyfrankie/favorite/internal/biz/social"
+	"github.com/crazyfrankie/favorite/internal/config"
+	"github.com/crazyfrankie/favorite/job/scheduler"
+	"github.com/crazyfrankie/favorite/pkg/ratelimit"
+	"github.com/crazyfrankie/favorite/rpc"
 )
 
 func InitDB() *gorm.DB {
@@ -31,13 +40,20 @@ func InitDB() *gorm.DB {
 			SingularTable: true,
 		},
 	})
-
-	db.AutoMigrate(&dao.FavoriteCount{}, &dao.UserFavorite{})
-
 	if err != nil {
 		panic(err)
 	}
 
+	if err := dao.DedupeUserFavorites(db); err != nil {
+		panic(err)
+	}
+	if err := db.AutoMigrate(&dao.FavoriteCount{}, &dao.UserFavorite{}, &dao.UserPrivacy{}, &dao.EraseTask{}, &dao.RelatedContent{}, &dao.ContentOwner{}, &dao.FavoriteCountHistory{}); err != nil {
+		panic(err)
+	}
+	if err := dao.InitActionLogTable(db); err != nil {
+		panic(err)
+	}
+
 	return db
 }
 
@@ -49,15 +65,101 @@ func InitCache() redis.Cmdable {
 	return cli
 }
 
-func InitServer() *service.FavoriteServer {
+func InitRegistry() *clientv3.Client {
+	cli, err := clientv3.New(clientv3.Config{
+		Endpoints:   []string{config.GetConf().ETCD.EndPoints},
+		DialTimeout: time.Second * 2,
+	})
+	if err != nil {
+		panic(err)
+	}
+
+	return cli
+}
+
+// InitJobLocker 未启用时返回 nil, 定时任务在每个实例上都会执行
+func InitJobLocker(cli *clientv3.Client) scheduler.JobLocker {
+	conf := config.GetConf().JobLock
+	if !conf.Enabled {
+		return nil
+	}
+
+	return scheduler.NewEtcdLocker(cli, conf.TTL, conf.MinHold)
+}
+
+// InitPauseStore 任务暂停状态保存在 etcd 中, 对所有实例生效
+func InitPauseStore(cli *clientv3.Client) scheduler.PauseStore {
+	return scheduler.NewEtcdPauseStore(cli)
+}
+
+func InitAntiAbuse(cmd redis.Cmdable) *antiabuse.Pipeline {
+	conf := config.GetConf().AntiAbuse
+	if !conf.Enabled {
+		return antiabuse.NewPipeline(conf.Threshold)
+	}
+
+	return antiabuse.NewPipeline(conf.Threshold,
+		antiabuse.NewBurstScorer(cmd, conf.Burst.Window, conf.Burst.FreshAge, conf.Burst.Limit),
+		antiabuse.NewCycleScorer(cmd, conf.Cycle.Window, conf.Cycle.Limit),
+	)
+}
+
+func InitProbes(db *gorm.DB, cmd redis.Cmdable) rpc.Probes {
+	return rpc.Probes{
+		{
+			Name: "redis",
+			Check: func(ctx context.Context) error {
+				return cmd.Ping(ctx).Err()
+			},
+		},
+		{
+			Name: "mysql",
+			Check: func(ctx context.Context) error {
+				sqlDB, err := db.DB()
+				if err != nil {
+					return err
+				}
+				return sqlDB.PingContext(ctx)
+			},
+		},
+	}
+}
+
+func InitRepo() *repository.FavoriteRepo {
 	wire.Build(
 		InitDB,
 		InitCache,
-		dao.NewFavoriteDao,
+		dao.NewFavoriteWriteDao,
+		dao.NewFavoriteReadDao,
+		cache.NewFavoriteCache,
+		repository.NewFavoriteRepo,
+	)
+
+	return new(repository.FavoriteRepo)
+}
+
+func InitApp() *App {
+	wire.Build(
+		InitDB,
+		InitCache,
+		InitRegistry,
+		InitJobLocker,
+		InitPauseStore,
+		InitAntiAbuse,
+		InitProbes,
+		social.NewNoopGraph,
+		dao.NewFavoriteWriteDao,
+		dao.NewFavoriteReadDao,
 		cache.NewFavoriteCache,
 		repository.NewFavoriteRepo,
 		service.NewFavoriteServer,
+		ratelimit.NewRedisSlidingWindowLimiter,
+		rpc.NewServer,
+		events.NewContentConsumer,
+		events.NewCountHub,
+
+		wire.Struct(new(App), "*"),
 	)
 
-	return new(service.FavoriteServer)
+	return new(App)
 }
@@ -7,29 +7,67 @@
 package ioc
 
 import (
+	"context"
 	"fmt"
+	"github.com/crazyfrankie/favorite/internal/biz/antiabuse"
+	"github.com/crazyfrankie/favorite/internal/biz/events"
 	"github.com/crazyfrankie/favorite/internal/biz/repository"
 	"github.com/crazyfrankie/favorite/internal/biz/repository/cache"
-	dao2 "github.com/crazyfrankie/favorite/internal/biz/repository/dao"
+	"github.com/crazyfrankie/favorite/internal/biz/repository/dao"
 	"github.com/crazyfrankie/favorite/internal/biz/service"
+	"github.com/crazyfrankie/favorite/internal/biz/social"
 	"github.com/crazyfrankie/favorite/internal/config"
+	"github.com/crazyfrankie/favorite/job/scheduler"
+	"github.com/crazyfrankie/favorite/pkg/ratelimit"
+	"github.com/crazyfrankie/favorite/rpc"
 	"github.com/redis/go-redis/v9"
+	"go.etcd.io/etcd/client/v3"
 	"gorm.io/driver/mysql"
 	"gorm.io/gorm"
 	"gorm.io/gorm/schema"
 	"os"
+	"time"
 )
 
 // Injectors from wire.go:
 
-func InitServer() *service.FavoriteServer {
+func InitRepo() *repository.FavoriteRepo {
 	cmdable := InitCache()
 	favoriteCache := cache.NewFavoriteCache(cmdable)
 	db := InitDB()
-	favoriteDao := dao2.NewFavoriteDao(db)
-	favoriteRepo := repository.NewFavoriteRepo(favoriteCache, favoriteDao)
-	favoriteServer := service.NewFavoriteServer(favoriteRepo)
-	return favoriteServer
+	favoriteWriteDao := dao.NewFavoriteWriteDao(db)
+	favoriteReadDao := dao.NewFavoriteReadDao(db)
+	favoriteRepo := repository.NewFavoriteRepo(favoriteCache, favoriteWriteDao, favoriteReadDao)
+	return favoriteRepo
+}
+
+func InitApp() *App {
+	client := InitRegistry()
+	cmdable := InitCache()
+	favoriteCache := cache.NewFavoriteCache(cmdable)
+	db := InitDB()
+	favoriteWriteDao := dao.NewFavoriteWriteDao(db)
+	favoriteReadDao := dao.NewFavoriteReadDao(db)
+	favoriteRepo := repository.NewFavoriteRepo(favoriteCache, favoriteWriteDao, favoriteReadDao)
+	pipeline := InitAntiAbuse(cmdable)
+	graph := social.NewNoopGraph()
+	countHub := events.NewCountHub(favoriteRepo)
+	favoriteServer := service.NewFavoriteServer(favoriteRepo, pipeline, graph, countHub)
+	limiter := ratelimit.NewRedisSlidingWindowLimiter(cmdable)
+	probes := InitProbes(db, cmdable)
+	server := rpc.NewServer(client, favoriteServer, limiter, probes)
+	contentConsumer := events.NewContentConsumer(cmdable, favoriteRepo)
+	jobLocker := InitJobLocker(client)
+	pauseStore := InitPauseStore(client)
+	app := &App{
+		Server:   server,
+		Favorite: favoriteServer,
+		Content:  contentConsumer,
+		Counts:   countHub,
+		Locker:   jobLocker,
+		Pauses:   pauseStore,
+	}
+	return app
 }
 
 // wire.go:
@@ -41,13 +79,20 @@ func InitDB() *gorm.DB {
 			SingularTable: true,
 		},
 	})
-
-	db.AutoMigrate(&dao2.FavoriteCount{}, &dao2.UserFavorite{})
-
 	if err != nil {
 		panic(err)
 	}
 
+	if err := dao.DedupeUserFavorites(db); err != nil {
+		panic(err)
+	}
+	if err := db.AutoMigrate(&dao.FavoriteCount{}, &dao.UserFavorite{}, &dao.UserPrivacy{}, &dao.EraseTask{}, &dao.RelatedContent{}, &dao.ContentOwner{}, &dao.FavoriteCountHistory{}); err != nil {
+		panic(err)
+	}
+	if err := dao.InitActionLogTable(db); err != nil {
+		panic(err)
+	}
+
 	return db
 }
 
@@ -58,3 +103,60 @@ func InitCache() redis.Cmdable {
 
 	return cli
 }
+
+func InitRegistry() *clientv3.Client {
+	cli, err := clientv3.New(clientv3.Config{
+		Endpoints:   []string{config.GetConf().ETCD.EndPoints},
+		DialTimeout: time.Second * 2,
+	})
+	if err != nil {
+		panic(err)
+	}
+
+	return cli
+}
+
+// InitJobLocker 未启用时返回 nil, 定时任务在每个实例上都会执行
+func InitJobLocker(cli *clientv3.Client) scheduler.JobLocker {
+	conf := config.GetConf().JobLock
+	if !conf.Enabled {
+		return nil
+	}
+
+	return scheduler.NewEtcdLocker(cli, conf.TTL, conf.MinHold)
+}
+
+// InitPauseStore 任务暂停状态保存在 etcd 中, 对所有实例生效
+func InitPauseStore(cli *clientv3.Client) scheduler.PauseStore {
+	return scheduler.NewEtcdPauseStore(cli)
+}
+
+func InitAntiAbuse(cmd redis.Cmdable) *antiabuse.Pipeline {
+	conf := config.GetConf().AntiAbuse
+	if !conf.Enabled {
+		return antiabuse.NewPipeline(conf.Threshold)
+	}
+
+	return antiabuse.NewPipeline(conf.Threshold, antiabuse.NewBurstScorer(cmd, conf.Burst.Window, conf.Burst.FreshAge, conf.Burst.Limit), antiabuse.NewCycleScorer(cmd, conf.Cycle.Window, conf.Cycle.Limit))
+}
+
+func InitProbes(db *gorm.DB, cmd redis.Cmdable) rpc.Probes {
+	return rpc.Probes{
+		{
+			Name: "redis",
+			Check: func(ctx context.Context) error {
+				return cmd.Ping(ctx).Err()
+			},
+		},
+		{
+			Name: "mysql",
+			Check: func(ctx context.Context) error {
+				sqlDB, err := db.DB()
+				if err != nil {
+					return err
+				}
+				return sqlDB.PingContext(ctx)
+			},
+		},
+	}
+}
@@ -1,6 +1,9 @@
 package job
 
+import "context"
+
 type Job interface {
 	Name() string
-	Run() error
+	// Run 执行一轮任务, ctx 在任务锁丢失时被取消, 任务应尽快停止
+	Run(ctx context.Context) error
 }
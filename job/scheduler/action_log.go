@@ -0,0 +1,39 @@
+package scheduler
+
+import (
+	"context"
+	"time"
+
+	"github.com/crazyfrankie/favorite/internal/biz/service"
+)
+
+// ActionLogScheduler 定时预建点赞操作流水表的月分区
+type ActionLogScheduler struct {
+	opt *option
+	svc *service.FavoriteServer
+}
+
+func NewActionLogScheduler(svc *service.FavoriteServer, opts ...Option) *ActionLogScheduler {
+	opt := &option{
+		timeout: time.Minute,
+	}
+	for _, o := range opts {
+		o(opt)
+	}
+
+	return &ActionLogScheduler{
+		opt: opt,
+		svc: svc,
+	}
+}
+
+func (s *ActionLogScheduler) Name() string {
+	return "action_log_partition"
+}
+
+func (s *ActionLogScheduler) Run(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, s.opt.timeout)
+	defer cancel()
+
+	return s.svc.MaintainActionLog(ctx)
+}
@@ -0,0 +1,93 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/crazyfrankie/favorite/api/rpc_gen/favorite"
+)
+
+// AdminServer 定时任务管理接口
+type AdminServer struct {
+	favorite.UnimplementedJobAdminServiceServer
+	builder *CronJobBuilder
+}
+
+func NewAdminServer(builder *CronJobBuilder) *AdminServer {
+	return &AdminServer{builder: builder}
+}
+
+func (s *AdminServer) ListJobs(ctx context.Context, req *favorite.ListJobsRequest) (*favorite.ListJobsResponse, error) {
+	jobs := s.builder.Jobs()
+
+	res := make([]*favorite.JobInfo, 0, len(jobs))
+	for _, j := range jobs {
+		info := &favorite.JobInfo{
+			Name:       j.Name,
+			Schedule:   j.Schedule,
+			Paused:     j.Paused,
+			Disabled:   j.Disabled,
+			LastRunId:  j.LastRunID,
+			LastStatus: j.Status(),
+			LastError:  j.LastError,
+			Holder:     j.Holder,
+		}
+		if !j.LastRun.IsZero() {
+			info.LastRun = j.LastRun.Unix()
+		}
+		if !j.NextRun.IsZero() {
+			info.NextRun = j.NextRun.Unix()
+		}
+		res = append(res, info)
+	}
+
+	return &favorite.ListJobsResponse{Jobs: res}, nil
+}
+
+func (s *AdminServer) PauseJob(ctx context.Context, req *favorite.PauseJobRequest) (*favorite.PauseJobResponse, error) {
+	if err := s.builder.Pause(ctx, req.GetName()); err != nil {
+		return nil, jobError(err)
+	}
+
+	return &favorite.PauseJobResponse{}, nil
+}
+
+func (s *AdminServer) ResumeJob(ctx context.Context, req *favorite.ResumeJobRequest) (*favorite.ResumeJobResponse, error) {
+	if err := s.builder.Resume(ctx, req.GetName()); err != nil {
+		return nil, jobError(err)
+	}
+
+	return &favorite.ResumeJobResponse{}, nil
+}
+
+func (s *AdminServer) TriggerJob(ctx context.Context, req *favorite.TriggerJobRequest) (*favorite.TriggerJobResponse, error) {
+	runID, done, err := s.builder.Trigger(req.GetName())
+	if err != nil {
+		return nil, jobError(err)
+	}
+
+	// 调用方不再等待时任务继续执行, 结果可通过 ListJobs 查询
+	select {
+	case err := <-done:
+		if err != nil {
+			return &favorite.TriggerJobResponse{RunId: runID, Status: RunFailed, Error: err.Error()}, nil
+		}
+		return &favorite.TriggerJobResponse{RunId: runID, Status: RunSucceeded}, nil
+	case <-ctx.Done():
+		return &favorite.TriggerJobResponse{RunId: runID, Status: RunRunning}, nil
+	}
+}
+
+func jobError(err error) error {
+	switch {
+	case errors.Is(err, ErrJobNotFound):
+		return status.Errorf(codes.NotFound, "%v", err)
+	case errors.Is(err, ErrJobRunning), errors.Is(err, ErrLockHeld):
+		return status.Errorf(codes.FailedPrecondition, "%v", err)
+	}
+
+	return status.Errorf(codes.Internal, "%v", err)
+}
@@ -1,58 +1,554 @@
 package scheduler
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
 	"strconv"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/robfig/cron/v3"
 	"go.uber.org/zap"
 
+	"github.com/crazyfrankie/favorite/internal/config"
 	"github.com/crazyfrankie/favorite/job"
 )
 
+const (
+	RunRunning   = 1
+	RunSucceeded = 2
+	RunFailed    = 3
+)
+
+var (
+	ErrJobNotFound = errors.New("job not found")
+	ErrJobRunning  = errors.New("job is already running")
+)
+
+// Factory 按选项创建任务, 配置变更时用于重建任务
+type Factory func(opts ...Option) job.Job
+
+// localJob 实现该接口且 Local 返回 true 的任务在每个实例上都会执行, 不抢占任务锁
+type localJob interface {
+	Local() bool
+}
+
+func isLocal(j job.Job) bool {
+	l, ok := j.(localJob)
+	return ok && l.Local()
+}
+
 type CronJobBuilder struct {
-	metric *prometheus.SummaryVec
-	log    *zap.Logger
+	duration    *prometheus.HistogramVec
+	runs        *prometheus.CounterVec
+	lastRun     *prometheus.GaugeVec
+	lastSuccess *prometheus.GaugeVec
+	lockHeld    *prometheus.GaugeVec
+	skipped     *prometheus.CounterVec
+	locker      JobLocker
+	pauses      PauseStore
+	log         *zap.Logger
+	seq         atomic.Int64
+
+	mu   sync.RWMutex
+	cr   *cron.Cron
+	jobs map[string]*jobState
+}
+
+// jobState 任务的调度配置及最近一次的执行情况
+type jobState struct {
+	name        string
+	factory     Factory
+	defaultSpec string
+
+	job      job.Job
+	schedule string
+	// 为 0 表示当前未调度
+	entry    cron.EntryID
+	disabled bool
+	paused   bool
+
+	running      bool
+	lastRunID    string
+	lastStart    time.Time
+	lastDuration time.Duration
+	lastErr      error
+}
+
+// JobStatus 任务的调度信息及最近一次执行结果
+type JobStatus struct {
+	Name       string    `json:"name"`
+	Schedule   string    `json:"schedule"`
+	Paused     bool      `json:"paused"`
+	Disabled   bool      `json:"disabled"`
+	Running    bool      `json:"running"`
+	LastRunID  string    `json:"last_run_id,omitempty"`
+	LastRun    time.Time `json:"last_run"`
+	LastResult string    `json:"last_result,omitempty"`
+	LastError  string    `json:"last_error,omitempty"`
+	DurationMs int64     `json:"duration_ms"`
+	NextRun    time.Time `json:"next_run"`
+	// Holder 当前持有任务锁的实例, 未启用分布式锁时为空
+	Holder string `json:"holder,omitempty"`
 }
 
-func NewCronJobBuilder(l *zap.Logger) *CronJobBuilder {
-	metric := prometheus.NewSummaryVec(prometheus.SummaryOpts{}, []string{"name", "success"})
-	prometheus.MustRegister(metric)
+// Status 最近一次执行的状态, 取值为 RunRunning 等, 从未执行时为 0
+func (s JobStatus) Status() int32 {
+	switch {
+	case s.Running:
+		return RunRunning
+	case s.LastResult == "success":
+		return RunSucceeded
+	case s.LastResult == "failed":
+		return RunFailed
+	}
+
+	return 0
+}
+
+// jobRun 单次执行
+type jobRun struct {
+	id    string
+	name  string
+	job   job.Job
+	lock  JobLock
+	start time.Time
+}
+
+// NewCronJobBuilder reg 为任务指标注册的 registry, 需与 /metrics 暴露的保持一致;
+// locker 为空时每个实例都会执行任务, pauses 为空时暂停/恢复只作用于当前实例
+func NewCronJobBuilder(cr *cron.Cron, l *zap.Logger, reg prometheus.Registerer, locker JobLocker, pauses PauseStore) *CronJobBuilder {
+	duration := prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: "favorite",
+		Subsystem: "job",
+		Name:      "duration_seconds",
+		Help:      "Duration of scheduled job runs.",
+		Buckets:   []float64{0.1, 0.5, 1, 5, 15, 60, 300, 900, 3600},
+	}, []string{"name", "success"})
+	runs := prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "favorite",
+		Subsystem: "job",
+		Name:      "runs_total",
+		Help:      "Number of scheduled job runs.",
+	}, []string{"name", "success"})
+	lastRun := prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "favorite",
+		Subsystem: "job",
+		Name:      "last_run_timestamp_seconds",
+		Help:      "Unix time the job last finished.",
+	}, []string{"name"})
+	lastSuccess := prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "favorite",
+		Subsystem: "job",
+		Name:      "last_success_timestamp_seconds",
+		Help:      "Unix time the job last finished successfully.",
+	}, []string{"name"})
+	lockHeld := prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "favorite",
+		Subsystem: "job",
+		Name:      "lock_held",
+		Help:      "Whether the instance currently holds the job lock.",
+	}, []string{"name", "instance"})
+	skipped := prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "favorite",
+		Subsystem: "job",
+		Name:      "skipped_total",
+		Help:      "Number of job runs skipped because the lock was not acquired.",
+	}, []string{"name", "reason"})
+	reg.MustRegister(duration, runs, lastRun, lastSuccess, lockHeld, skipped)
 
 	return &CronJobBuilder{
-		metric: metric,
-		log:    l,
+		duration:    duration,
+		runs:        runs,
+		lastRun:     lastRun,
+		lastSuccess: lastSuccess,
+		lockHeld:    lockHeld,
+		skipped:     skipped,
+		locker:      locker,
+		pauses:      pauses,
+		log:         l,
+		cr:          cr,
+		jobs:        make(map[string]*jobState),
 	}
 }
 
-func (c *CronJobBuilder) Builder(j job.Job) cron.Job {
-	name := j.Name()
+// Register 注册任务, spec 为默认调度规则; 注册后需调用 Apply 才会被调度
+func (c *CronJobBuilder) Register(spec string, f Factory) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
 
-	return cronJob(func() error {
-		start := time.Now()
-		c.log.Debug("任务开始",
-			zap.String("name", name),
-			zap.String("time", start.String()))
-
-		var success bool
-		defer func() {
-			duration := time.Since(start).Milliseconds()
-			c.log.Debug("任务结束",
-				zap.String("name", name))
-			c.metric.WithLabelValues(name,
-				strconv.FormatBool(success)).Observe(float64(duration))
-		}()
-		err := j.Run()
-		success = err == nil
-		if err != nil {
-			c.log.Error("任务执行失败",
+	j := f()
+	c.jobs[j.Name()] = &jobState{
+		name:        j.Name(),
+		factory:     f,
+		defaultSpec: spec,
+		job:         j,
+	}
+}
+
+// Apply 按配置重建并调度所有已注册任务, 配置变更时可重复调用;
+// 某个任务的配置有误时保留其原有调度, 不影响其他任务
+func (c *CronJobBuilder) Apply(confs map[string]config.Job) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var errs []error
+	for name, s := range c.jobs {
+		conf := confs[name]
+
+		spec := conf.Spec
+		if spec == "" {
+			spec = s.defaultSpec
+		}
+		var opts []Option
+		if conf.Timeout > 0 {
+			opts = append(opts, WithTimeout(conf.Timeout))
+		}
+		if conf.Threshold > 0 {
+			opts = append(opts, WithThreshold(conf.Threshold))
+		}
+
+		if err := c.schedule(s, spec, conf.Disabled); err != nil {
+			errs = append(errs, fmt.Errorf("job %s: %w", name, err))
+			continue
+		}
+		// 正在执行的一轮不受影响, 下一轮起使用新的选项
+		s.job = s.factory(opts...)
+	}
+
+	return errors.Join(errs...)
+}
+
+// Pause 暂停任务的定时调度, 通过 PauseStore 同步到所有实例, 正在执行的一轮不受影响
+func (c *CronJobBuilder) Pause(ctx context.Context, name string) error {
+	return c.setPaused(ctx, name, true)
+}
+
+// Resume 恢复被 Pause 的任务
+func (c *CronJobBuilder) Resume(ctx context.Context, name string) error {
+	return c.setPaused(ctx, name, false)
+}
+
+// WatchPaused 同步其他实例设置的暂停状态, 阻塞直到 ctx 被取消, 监听中断时自动重试
+func (c *CronJobBuilder) WatchPaused(ctx context.Context) error {
+	if c.pauses == nil {
+		<-ctx.Done()
+		return nil
+	}
+
+	for {
+		err := c.pauses.Watch(ctx, func(paused map[string]bool) {
+			c.applyPaused(paused, true)
+		})
+		if ctx.Err() != nil {
+			return nil
+		}
+		c.log.Error("监听任务暂停状态失败", zap.Error(err))
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(time.Second):
+		}
+	}
+}
+
+func (c *CronJobBuilder) setPaused(ctx context.Context, name string, paused bool) error {
+	c.mu.RLock()
+	_, ok := c.jobs[name]
+	c.mu.RUnlock()
+	if !ok {
+		return ErrJobNotFound
+	}
+
+	if c.pauses != nil {
+		if err := c.pauses.SetPaused(ctx, name, paused); err != nil {
+			return err
+		}
+	}
+	// 不等待 watch 回调, 本实例立即生效
+	c.applyPaused(map[string]bool{name: paused}, false)
+
+	return nil
+}
+
+// applyPaused 按 paused 调整任务的调度; full 为 true 时 paused 为全量状态, 不在其中的任务视为未暂停,
+// 否则不在其中的任务保持原状态
+func (c *CronJobBuilder) applyPaused(paused map[string]bool, full bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for name, s := range c.jobs {
+		p, ok := paused[name]
+		if !ok && !full {
+			continue
+		}
+		if p == s.paused {
+			continue
+		}
+		s.paused = p
+		if err := c.schedule(s, s.schedule, s.disabled); err != nil {
+			c.log.Error("调整任务调度失败",
 				zap.String("name", name),
 				zap.Error(err))
 		}
+	}
+}
+
+// Trigger 立即执行一次任务, 不受暂停或停用影响, 返回的 channel 在任务结束后写入执行结果
+func (c *CronJobBuilder) Trigger(name string) (string, <-chan error, error) {
+	r, err := c.begin(name)
+	if err != nil {
+		return "", nil, err
+	}
 
+	ch := make(chan error, 1)
+	go func() {
+		ch <- c.exec(r)
+	}()
+
+	return r.id, ch, nil
+}
+
+// schedule 按 spec 调整任务在 cron 中的调度, 须持有 c.mu
+func (c *CronJobBuilder) schedule(s *jobState, spec string, disabled bool) error {
+	if disabled || s.paused {
+		if s.entry != 0 {
+			c.cr.Remove(s.entry)
+			s.entry = 0
+		}
+		s.schedule = spec
+		s.disabled = disabled
 		return nil
+	}
+	if s.entry != 0 && s.schedule == spec {
+		s.disabled = disabled
+		return nil
+	}
+
+	// 先添加再移除, spec 有误时保留原调度
+	name := s.name
+	id, err := c.cr.AddJob(spec, cronJob(func() error {
+		r, err := c.begin(name)
+		if err != nil {
+			return err
+		}
+		return c.exec(r)
+	}))
+	if err != nil {
+		return err
+	}
+	if s.entry != 0 {
+		c.cr.Remove(s.entry)
+	}
+	s.entry = id
+	s.schedule = spec
+	s.disabled = disabled
+
+	return nil
+}
+
+// begin 标记任务开始执行并抢占任务锁, 同一实例上同一任务不会并发执行
+func (c *CronJobBuilder) begin(name string) (*jobRun, error) {
+	c.mu.Lock()
+	s, ok := c.jobs[name]
+	if !ok {
+		c.mu.Unlock()
+		return nil, ErrJobNotFound
+	}
+	if s.running {
+		c.mu.Unlock()
+		c.log.Warn("上一轮任务尚未结束, 跳过",
+			zap.String("name", name))
+		c.skipped.WithLabelValues(name, "running").Inc()
+		return nil, ErrJobRunning
+	}
+	s.running = true
+	r := &jobRun{
+		id:   fmt.Sprintf("%s-%d-%d", name, time.Now().Unix(), c.seq.Add(1)),
+		name: name,
+		job:  s.job,
+	}
+	c.mu.Unlock()
+
+	if c.locker != nil && !isLocal(r.job) {
+		lock, err := c.lock(name)
+		if err != nil {
+			c.update(name, func(s *jobState) {
+				s.running = false
+			})
+			return nil, err
+		}
+		r.lock = lock
+		c.lockHeld.WithLabelValues(name, c.locker.Instance()).Set(1)
+	}
+
+	r.start = time.Now()
+	c.update(name, func(s *jobState) {
+		s.lastRunID = r.id
+		s.lastStart = r.start
+	})
+
+	return r, nil
+}
+
+// exec 执行任务并记录结果, 结束后释放任务锁
+func (c *CronJobBuilder) exec(r *jobRun) (err error) {
+	c.log.Debug("任务开始",
+		zap.String("name", r.name),
+		zap.String("run_id", r.id),
+		zap.String("time", r.start.String()))
+
+	defer func() {
+		duration := time.Since(r.start)
+		success := strconv.FormatBool(err == nil)
+		c.log.Debug("任务结束",
+			zap.String("name", r.name),
+			zap.String("run_id", r.id))
+		c.duration.WithLabelValues(r.name, success).Observe(duration.Seconds())
+		c.runs.WithLabelValues(r.name, success).Inc()
+		c.lastRun.WithLabelValues(r.name).Set(float64(time.Now().Unix()))
+		if err == nil {
+			c.lastSuccess.WithLabelValues(r.name).Set(float64(time.Now().Unix()))
+		}
+		if r.lock != nil {
+			c.lockHeld.WithLabelValues(r.name, c.locker.Instance()).Set(0)
+			r.lock.Unlock()
+		}
+		c.update(r.name, func(s *jobState) {
+			s.running = false
+			s.lastDuration = duration
+			s.lastErr = err
+		})
+	}()
+
+	// 锁丢失后其他实例可能已开始执行同一任务, 取消本实例的执行
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	if r.lock != nil {
+		go func() {
+			select {
+			case <-r.lock.Lost():
+				c.log.Warn("任务锁已丢失, 取消执行",
+					zap.String("name", r.name),
+					zap.String("run_id", r.id))
+				cancel()
+			case <-ctx.Done():
+			}
+		}()
+	}
+
+	err = r.job.Run(ctx)
+	if err != nil {
+		c.log.Error("任务执行失败",
+			zap.String("name", r.name),
+			zap.String("run_id", r.id),
+			zap.Error(err))
+	}
+
+	return err
+}
+
+// lock 抢占任务锁, 未抢到说明本轮已由其他实例执行
+func (c *CronJobBuilder) lock(name string) (JobLock, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	lock, err := c.locker.TryLock(ctx, name)
+	switch {
+	case errors.Is(err, ErrLockHeld):
+		c.log.Debug("任务锁已被其他实例持有, 跳过",
+			zap.String("name", name))
+		c.skipped.WithLabelValues(name, "held").Inc()
+	case err != nil:
+		c.log.Error("获取任务锁失败",
+			zap.String("name", name),
+			zap.Error(err))
+		c.skipped.WithLabelValues(name, "error").Inc()
+	}
+
+	return lock, err
+}
+
+// Jobs 列出已注册的任务, 按名称排序
+func (c *CronJobBuilder) Jobs() []JobStatus {
+	res := c.jobStatus()
+	if c.locker == nil {
+		return res
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	for i := range res {
+		holder, err := c.locker.Holder(ctx, res[i].Name)
+		if err != nil {
+			c.log.Warn("查询任务锁持有者失败",
+				zap.String("name", res[i].Name),
+				zap.Error(err))
+			continue
+		}
+		res[i].Holder = holder
+	}
+
+	return res
+}
+
+func (c *CronJobBuilder) jobStatus() []JobStatus {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	res := make([]JobStatus, 0, len(c.jobs))
+	for _, s := range c.jobs {
+		st := JobStatus{
+			Name:       s.name,
+			Schedule:   s.schedule,
+			Paused:     s.paused,
+			Disabled:   s.disabled,
+			Running:    s.running,
+			LastRunID:  s.lastRunID,
+			LastRun:    s.lastStart,
+			DurationMs: s.lastDuration.Milliseconds(),
+		}
+		if !s.lastStart.IsZero() && !s.running {
+			st.LastResult = "success"
+			if s.lastErr != nil {
+				st.LastResult = "failed"
+				st.LastError = s.lastErr.Error()
+			}
+		}
+		if s.entry != 0 {
+			st.NextRun = c.cr.Entry(s.entry).Next
+		}
+		res = append(res, st)
+	}
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].Name < res[j].Name
 	})
+
+	return res
+}
+
+// StatusHandler 以 JSON 返回 Jobs 的结果
+func (c *CronJobBuilder) StatusHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(c.Jobs())
+	})
+}
+
+func (c *CronJobBuilder) update(name string, fn func(s *jobState)) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if s, ok := c.jobs[name]; ok {
+		fn(s)
+	}
 }
 
 type cronJob func() error
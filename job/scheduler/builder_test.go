@@ -0,0 +1,207 @@
+package scheduler
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/robfig/cron/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/crazyfrankie/favorite/job"
+)
+
+type fakeLocker struct {
+	mu     sync.Mutex
+	held   map[string]bool
+	locked []*fakeLock
+}
+
+func newFakeLocker() *fakeLocker {
+	return &fakeLocker{held: make(map[string]bool)}
+}
+
+func (l *fakeLocker) Instance() string {
+	return "test"
+}
+
+func (l *fakeLocker) TryLock(ctx context.Context, name string) (JobLock, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.held[name] {
+		return nil, ErrLockHeld
+	}
+	l.held[name] = true
+	lock := &fakeLock{locker: l, name: name, lost: make(chan struct{})}
+	l.locked = append(l.locked, lock)
+
+	return lock, nil
+}
+
+func (l *fakeLocker) Holder(ctx context.Context, name string) (string, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.held[name] {
+		return l.Instance(), nil
+	}
+	return "", nil
+}
+
+func (l *fakeLocker) Close() error {
+	return nil
+}
+
+func (l *fakeLocker) isHeld(name string) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.held[name]
+}
+
+type fakeLock struct {
+	locker *fakeLocker
+	name   string
+	lost   chan struct{}
+}
+
+func (l *fakeLock) Lost() <-chan struct{} {
+	return l.lost
+}
+
+func (l *fakeLock) Unlock() {
+	l.locker.mu.Lock()
+	defer l.locker.mu.Unlock()
+
+	delete(l.locker.held, l.name)
+}
+
+type testJob struct {
+	name  string
+	local bool
+	run   func(ctx context.Context) error
+}
+
+func (j *testJob) Name() string {
+	return j.name
+}
+
+func (j *testJob) Run(ctx context.Context) error {
+	return j.run(ctx)
+}
+
+func (j *testJob) Local() bool {
+	return j.local
+}
+
+func newTestBuilder(t *testing.T, locker JobLocker, j *testJob) *CronJobBuilder {
+	t.Helper()
+
+	b := NewCronJobBuilder(cron.New(), zap.NewNop(), prometheus.NewRegistry(), locker, nil)
+	b.Register("@every 1h", func(opts ...Option) job.Job { return j })
+
+	return b
+}
+
+func TestTriggerHoldsLock(t *testing.T) {
+	locker := newFakeLocker()
+	var heldDuringRun bool
+	b := newTestBuilder(t, locker, &testJob{name: "sync", run: func(ctx context.Context) error {
+		heldDuringRun = locker.isHeld("sync")
+		return nil
+	}})
+
+	_, done, err := b.Trigger("sync")
+	require.NoError(t, err)
+	require.NoError(t, <-done)
+
+	assert.True(t, heldDuringRun)
+	// 任务结束后释放锁
+	assert.False(t, locker.isHeld("sync"))
+	assert.Equal(t, float64(0), testutil.ToFloat64(b.lockHeld.WithLabelValues("sync", "test")))
+}
+
+func TestTriggerLockHeld(t *testing.T) {
+	locker := newFakeLocker()
+	locker.held["sync"] = true
+	ran := false
+	b := newTestBuilder(t, locker, &testJob{name: "sync", run: func(ctx context.Context) error {
+		ran = true
+		return nil
+	}})
+
+	_, _, err := b.Trigger("sync")
+	assert.ErrorIs(t, err, ErrLockHeld)
+	assert.False(t, ran)
+	assert.Equal(t, float64(1), testutil.ToFloat64(b.skipped.WithLabelValues("sync", "held")))
+
+	// 抢锁失败不应残留运行状态, 锁释放后可再次执行
+	delete(locker.held, "sync")
+	_, done, err := b.Trigger("sync")
+	require.NoError(t, err)
+	require.NoError(t, <-done)
+	assert.True(t, ran)
+}
+
+func TestLockLostCancelsRun(t *testing.T) {
+	locker := newFakeLocker()
+	started := make(chan struct{})
+	b := newTestBuilder(t, locker, &testJob{name: "sync", run: func(ctx context.Context) error {
+		close(started)
+		<-ctx.Done()
+		return ctx.Err()
+	}})
+
+	_, done, err := b.Trigger("sync")
+	require.NoError(t, err)
+	<-started
+	close(locker.locked[0].lost)
+
+	select {
+	case err := <-done:
+		assert.ErrorIs(t, err, context.Canceled)
+	case <-time.After(time.Second):
+		t.Fatal("job not canceled after lock lost")
+	}
+	assert.False(t, locker.isHeld("sync"))
+}
+
+func TestTriggerRunning(t *testing.T) {
+	release := make(chan struct{})
+	b := newTestBuilder(t, newFakeLocker(), &testJob{name: "sync", run: func(ctx context.Context) error {
+		<-release
+		return nil
+	}})
+
+	_, done, err := b.Trigger("sync")
+	require.NoError(t, err)
+
+	_, _, err = b.Trigger("sync")
+	assert.ErrorIs(t, err, ErrJobRunning)
+
+	close(release)
+	require.NoError(t, <-done)
+}
+
+func TestLocalJobSkipsLock(t *testing.T) {
+	locker := newFakeLocker()
+	locker.held["metrics"] = true
+	ran := false
+	b := newTestBuilder(t, locker, &testJob{name: "metrics", local: true, run: func(ctx context.Context) error {
+		ran = true
+		return nil
+	}})
+
+	// 每个实例都需执行的任务不抢占任务锁, 锁被其他实例持有时仍会执行
+	_, done, err := b.Trigger("metrics")
+	require.NoError(t, err)
+	require.NoError(t, <-done)
+	assert.True(t, ran)
+	assert.Len(t, locker.locked, 0)
+}
@@ -2,10 +2,12 @@ package scheduler
 
 import (
 	"context"
+	"time"
 
 	"github.com/crazyfrankie/favorite/internal/biz/service"
 )
 
+// DataScheduler 定时将缓存中的点赞数同步到数据库, 同时记录点赞数快照
 type DataScheduler struct {
 	opt *option
 	svc *service.FavoriteServer
@@ -13,7 +15,7 @@ type DataScheduler struct {
 
 func NewScheduler(svc *service.FavoriteServer, opts ...Option) *DataScheduler {
 	opt := &option{
-		timeout: 60,
+		timeout: 10 * time.Minute,
 	}
 	for _, o := range opts {
 		o(opt)
@@ -29,14 +31,10 @@ func (s *DataScheduler) Name() string {
 	return "data_sync"
 }
 
-func (s *DataScheduler) Run() error {
-	_, cancel := context.WithTimeout(context.Background(), s.opt.timeout)
+func (s *DataScheduler) Run(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, s.opt.timeout)
 	defer cancel()
-	if s.opt.threshold > 0 {
-		// 默认不采用阈值方式, 先校验, 如果设置了就走这条路
-		// 根据阈值判断是否持久化
-		// 先获取当前点赞数
-	}
 
-	return nil
+	// 默认不采用阈值方式; 设置阈值后, 点赞数变化量小于阈值的内容本轮不持久化
+	return s.svc.SyncFavoriteCounts(ctx, s.opt.threshold)
 }
@@ -0,0 +1,40 @@
+package scheduler
+
+import (
+	"context"
+	"time"
+
+	"github.com/crazyfrankie/favorite/internal/biz/service"
+)
+
+// EraseScheduler 定时续跑中断或失败的用户点赞擦除任务
+type EraseScheduler struct {
+	opt *option
+	svc *service.FavoriteServer
+}
+
+func NewEraseScheduler(svc *service.FavoriteServer, opts ...Option) *EraseScheduler {
+	opt := &option{
+		// 小于默认调度间隔, 未完成的任务由下一轮从断点继续
+		timeout: 4 * time.Minute,
+	}
+	for _, o := range opts {
+		o(opt)
+	}
+
+	return &EraseScheduler{
+		opt: opt,
+		svc: svc,
+	}
+}
+
+func (s *EraseScheduler) Name() string {
+	return "erase_resume"
+}
+
+func (s *EraseScheduler) Run(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, s.opt.timeout)
+	defer cancel()
+
+	return s.svc.ResumeEraseTasks(ctx)
+}
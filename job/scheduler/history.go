@@ -0,0 +1,39 @@
+package scheduler
+
+import (
+	"context"
+	"time"
+
+	"github.com/crazyfrankie/favorite/internal/biz/service"
+)
+
+// HistoryScheduler 定时汇总点赞数快照并清理过期数据
+type HistoryScheduler struct {
+	opt *option
+	svc *service.FavoriteServer
+}
+
+func NewHistoryScheduler(svc *service.FavoriteServer, opts ...Option) *HistoryScheduler {
+	opt := &option{
+		timeout: 30 * time.Minute,
+	}
+	for _, o := range opts {
+		o(opt)
+	}
+
+	return &HistoryScheduler{
+		opt: opt,
+		svc: svc,
+	}
+}
+
+func (s *HistoryScheduler) Name() string {
+	return "count_history"
+}
+
+func (s *HistoryScheduler) Run(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, s.opt.timeout)
+	defer cancel()
+
+	return s.svc.MaintainCountHistory(ctx)
+}
@@ -0,0 +1,196 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	clientv3 "go.etcd.io/etcd/client/v3"
+	"go.etcd.io/etcd/client/v3/concurrency"
+	"go.uber.org/zap"
+)
+
+const (
+	jobLockPrefix   = "/favorite/jobs/lock/"
+	jobHolderPrefix = "/favorite/jobs/holder/"
+
+	defaultLockTTL = 30
+	defaultMinHold = 10 * time.Second
+)
+
+// ErrLockHeld 任务锁已被其他实例持有
+var ErrLockHeld = errors.New("job lock held by another instance")
+
+// JobLocker 任务分布式锁, 保证同一任务同一时刻只在一个实例上执行
+type JobLocker interface {
+	// Instance 当前实例标识
+	Instance() string
+	// TryLock 尝试获取任务锁, 已被其他实例持有时返回 ErrLockHeld
+	TryLock(ctx context.Context, name string) (JobLock, error)
+	// Holder 查询当前持有任务锁的实例, 无人持有时返回空串
+	Holder(ctx context.Context, name string) (string, error)
+	// Close 释放当前实例持有的所有任务锁
+	Close() error
+}
+
+type JobLock interface {
+	// Lost 锁因租约失效而丢失时关闭
+	Lost() <-chan struct{}
+	Unlock()
+}
+
+// EtcdLocker 基于 etcd 租约的任务锁, 租约由 session 自动续约, 实例宕机后租约到期自动释放
+type EtcdLocker struct {
+	client   *clientv3.Client
+	instance string
+	ttl      int
+	minHold  time.Duration
+
+	mu      sync.Mutex
+	session *concurrency.Session
+}
+
+// NewEtcdLocker ttl 为锁租约时长(秒), minHold 为任务结束后锁至少保留的时长
+func NewEtcdLocker(client *clientv3.Client, ttl int, minHold time.Duration) *EtcdLocker {
+	if ttl <= 0 {
+		ttl = defaultLockTTL
+	}
+	if minHold <= 0 {
+		minHold = defaultMinHold
+	}
+
+	return &EtcdLocker{
+		client:   client,
+		instance: instanceID(),
+		ttl:      ttl,
+		minHold:  minHold,
+	}
+}
+
+func (l *EtcdLocker) Instance() string {
+	return l.instance
+}
+
+func (l *EtcdLocker) TryLock(ctx context.Context, name string) (JobLock, error) {
+	s, err := l.currentSession()
+	if err != nil {
+		return nil, err
+	}
+
+	m := concurrency.NewMutex(s, jobLockPrefix+name)
+	if err := m.TryLock(ctx); err != nil {
+		if errors.Is(err, concurrency.ErrLocked) {
+			return nil, ErrLockHeld
+		}
+		return nil, err
+	}
+
+	// 持有者信息与锁共用租约, 仅用于展示
+	if _, err := l.client.Put(ctx, jobHolderPrefix+name, l.instance, clientv3.WithLease(s.Lease())); err != nil {
+		zap.L().Warn("record job lock holder failed", zap.String("name", name), zap.Error(err))
+	}
+
+	return &etcdLock{
+		client:   l.client,
+		mutex:    m,
+		session:  s,
+		name:     name,
+		acquired: time.Now(),
+		minHold:  l.minHold,
+	}, nil
+}
+
+func (l *EtcdLocker) Holder(ctx context.Context, name string) (string, error) {
+	resp, err := l.client.Get(ctx, jobHolderPrefix+name)
+	if err != nil {
+		return "", err
+	}
+	if len(resp.Kvs) == 0 {
+		return "", nil
+	}
+
+	return string(resp.Kvs[0].Value), nil
+}
+
+// Close 撤销租约, 释放当前实例持有的所有任务锁
+func (l *EtcdLocker) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.session == nil {
+		return nil
+	}
+	err := l.session.Close()
+	l.session = nil
+
+	return err
+}
+
+// currentSession 租约失效(如 etcd 长时间不可达)后重新建立 session
+func (l *EtcdLocker) currentSession() (*concurrency.Session, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.session != nil {
+		select {
+		case <-l.session.Done():
+			zap.L().Warn("job lock session expired, recreating")
+		default:
+			return l.session, nil
+		}
+	}
+
+	s, err := concurrency.NewSession(l.client, concurrency.WithTTL(l.ttl))
+	if err != nil {
+		return nil, err
+	}
+	l.session = s
+
+	return s, nil
+}
+
+type etcdLock struct {
+	client   *clientv3.Client
+	mutex    *concurrency.Mutex
+	session  *concurrency.Session
+	name     string
+	acquired time.Time
+	minHold  time.Duration
+}
+
+func (l *etcdLock) Lost() <-chan struct{} {
+	return l.session.Done()
+}
+
+// Unlock 锁至少保留 minHold, 其余实例在同一轮触发时仍会抢锁失败
+func (l *etcdLock) Unlock() {
+	release := func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		if _, err := l.client.Delete(ctx, jobHolderPrefix+l.name); err != nil {
+			zap.L().Warn("delete job lock holder failed", zap.String("name", l.name), zap.Error(err))
+		}
+		if err := l.mutex.Unlock(ctx); err != nil {
+			zap.L().Error("release job lock failed", zap.String("name", l.name), zap.Error(err))
+		}
+	}
+
+	if wait := l.minHold - time.Since(l.acquired); wait > 0 {
+		time.AfterFunc(wait, release)
+		return
+	}
+	release()
+}
+
+func instanceID() string {
+	host, err := os.Hostname()
+	if err != nil {
+		host = "unknown"
+	}
+
+	return fmt.Sprintf("%s-%d", host, os.Getpid())
+}
@@ -0,0 +1,44 @@
+package scheduler
+
+import (
+	"context"
+	"time"
+
+	"github.com/crazyfrankie/favorite/internal/biz/service"
+)
+
+// MetricsScheduler 定时采集需要查询存储才能得到的领域指标
+type MetricsScheduler struct {
+	opt *option
+	svc *service.FavoriteServer
+}
+
+func NewMetricsScheduler(svc *service.FavoriteServer, opts ...Option) *MetricsScheduler {
+	opt := &option{
+		timeout: 20 * time.Second,
+	}
+	for _, o := range opts {
+		o(opt)
+	}
+
+	return &MetricsScheduler{
+		opt: opt,
+		svc: svc,
+	}
+}
+
+func (s *MetricsScheduler) Name() string {
+	return "metrics_collect"
+}
+
+// Local 指标由每个实例各自暴露, 采集需在每个实例上执行, 不参与任务锁的抢占
+func (s *MetricsScheduler) Local() bool {
+	return true
+}
+
+func (s *MetricsScheduler) Run(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, s.opt.timeout)
+	defer cancel()
+
+	return s.svc.CollectMetrics(ctx)
+}
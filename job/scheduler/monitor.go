@@ -1,5 +1,7 @@
 package scheduler
 
+import "context"
+
 type MonitorScheduler struct {
 	// 监控间隔
 	interval int
@@ -14,7 +16,7 @@ func (m MonitorScheduler) Name() string {
 }
 
 // Run 监控业务系统的健康程度, 灵活切换数据同步方式
-func (m MonitorScheduler) Run() error {
+func (m MonitorScheduler) Run(ctx context.Context) error {
 	//TODO implement me
 	panic("implement me")
 }
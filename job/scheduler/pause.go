@@ -0,0 +1,70 @@
+package scheduler
+
+import (
+	"context"
+	"maps"
+	"strings"
+
+	"go.etcd.io/etcd/api/v3/mvccpb"
+	clientv3 "go.etcd.io/etcd/client/v3"
+)
+
+const jobPausedPrefix = "/favorite/jobs/paused/"
+
+// PauseStore 持久化任务的暂停状态, 使暂停/恢复作用于所有实例
+type PauseStore interface {
+	SetPaused(ctx context.Context, name string, paused bool) error
+	// Watch 先以当前已暂停的任务调用一次 fn, 之后每次变化时以最新的全量状态再次调用, 阻塞直到 ctx 被取消或监听中断
+	Watch(ctx context.Context, fn func(paused map[string]bool)) error
+}
+
+// EtcdPauseStore 以 etcd 中是否存在 jobPausedPrefix+name 表示任务是否暂停
+type EtcdPauseStore struct {
+	client *clientv3.Client
+}
+
+func NewEtcdPauseStore(client *clientv3.Client) *EtcdPauseStore {
+	return &EtcdPauseStore{client: client}
+}
+
+func (s *EtcdPauseStore) SetPaused(ctx context.Context, name string, paused bool) error {
+	if paused {
+		_, err := s.client.Put(ctx, jobPausedPrefix+name, "1")
+		return err
+	}
+
+	_, err := s.client.Delete(ctx, jobPausedPrefix+name)
+	return err
+}
+
+func (s *EtcdPauseStore) Watch(ctx context.Context, fn func(paused map[string]bool)) error {
+	resp, err := s.client.Get(ctx, jobPausedPrefix, clientv3.WithPrefix())
+	if err != nil {
+		return err
+	}
+
+	paused := make(map[string]bool, len(resp.Kvs))
+	for _, kv := range resp.Kvs {
+		paused[strings.TrimPrefix(string(kv.Key), jobPausedPrefix)] = true
+	}
+	fn(maps.Clone(paused))
+
+	// 从快照之后的版本开始监听, 不会遗漏两者之间的变化
+	ch := s.client.Watch(ctx, jobPausedPrefix, clientv3.WithPrefix(), clientv3.WithRev(resp.Header.Revision+1))
+	for wresp := range ch {
+		if err := wresp.Err(); err != nil {
+			return err
+		}
+		for _, ev := range wresp.Events {
+			name := strings.TrimPrefix(string(ev.Kv.Key), jobPausedPrefix)
+			if ev.Type == mvccpb.DELETE {
+				delete(paused, name)
+			} else {
+				paused[name] = true
+			}
+		}
+		fn(maps.Clone(paused))
+	}
+
+	return ctx.Err()
+}
@@ -0,0 +1,39 @@
+package scheduler
+
+import (
+	"context"
+	"time"
+
+	"github.com/crazyfrankie/favorite/internal/biz/service"
+)
+
+// RelatedScheduler 定时根据共同点赞用户重新计算相关内容
+type RelatedScheduler struct {
+	opt *option
+	svc *service.FavoriteServer
+}
+
+func NewRelatedScheduler(svc *service.FavoriteServer, opts ...Option) *RelatedScheduler {
+	opt := &option{
+		timeout: time.Hour,
+	}
+	for _, o := range opts {
+		o(opt)
+	}
+
+	return &RelatedScheduler{
+		opt: opt,
+		svc: svc,
+	}
+}
+
+func (s *RelatedScheduler) Name() string {
+	return "related_content"
+}
+
+func (s *RelatedScheduler) Run(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, s.opt.timeout)
+	defer cancel()
+
+	return s.svc.ComputeRelatedContent(ctx)
+}
@@ -0,0 +1,90 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"slices"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+var (
+	ErrInvalidToken = errors.New("invalid token")
+)
+
+const (
+	// RoleAdmin 运营后台等管理端
+	RoleAdmin = "admin"
+	// RoleService 内部服务间调用, 如内容服务
+	RoleService = "service"
+)
+
+type Claims struct {
+	UserId int64  `json:"user_id"`
+	Role   string `json:"role,omitempty"`
+	// Tier 用户等级, 用于选择限流规则
+	Tier string `json:"tier,omitempty"`
+	// SignupAt 账号注册时间, 秒级时间戳, 用于反作弊识别新账号
+	SignupAt int64 `json:"signup_at,omitempty"`
+	jwt.RegisteredClaims
+}
+
+// Identity 已认证的调用方, 服务或管理端 token 可不带用户 ID
+type Identity struct {
+	UserId   int64
+	Role     string
+	Tier     string
+	SignupAt int64
+}
+
+// ParseToken 校验 token 并解析出调用方身份
+func ParseToken(token string, secret []byte) (Identity, error) {
+	claims := &Claims{}
+	t, err := jwt.ParseWithClaims(token, claims, func(t *jwt.Token) (any, error) {
+		if _, ok := t.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, ErrInvalidToken
+		}
+		return secret, nil
+	})
+	if err != nil {
+		return Identity{}, err
+	}
+	if !t.Valid || claims.UserId < 0 || (claims.UserId == 0 && claims.Role == "") {
+		return Identity{}, ErrInvalidToken
+	}
+
+	return Identity{UserId: claims.UserId, Role: claims.Role, Tier: claims.Tier, SignupAt: claims.SignupAt}, nil
+}
+
+type callerKey struct{}
+
+// WithCaller 将已认证的调用方身份写入 context
+func WithCaller(ctx context.Context, id Identity) context.Context {
+	return context.WithValue(ctx, callerKey{}, id)
+}
+
+// IdentityFromContext 获取已认证的调用方身份, 匿名调用时返回 false
+func IdentityFromContext(ctx context.Context) (Identity, bool) {
+	id, ok := ctx.Value(callerKey{}).(Identity)
+	return id, ok
+}
+
+// CallerFromContext 获取已认证的调用方用户 ID, 未认证或 token 不代表具体用户时返回 false
+func CallerFromContext(ctx context.Context) (int64, bool) {
+	id, ok := IdentityFromContext(ctx)
+	if !ok || id.UserId <= 0 {
+		return 0, false
+	}
+
+	return id.UserId, true
+}
+
+// HasRole 调用方是否已认证且拥有 roles 中的任一角色
+func HasRole(ctx context.Context, roles ...string) bool {
+	id, ok := IdentityFromContext(ctx)
+	if !ok || id.Role == "" {
+		return false
+	}
+
+	return slices.Contains(roles, id.Role)
+}
@@ -0,0 +1,83 @@
+package client
+
+import (
+	"sync"
+
+	"google.golang.org/grpc/balancer"
+	"google.golang.org/grpc/balancer/base"
+)
+
+const (
+	// RoundRobin gRPC 内置的轮询负载均衡
+	RoundRobin = "round_robin"
+	// Weighted 按服务注册时的 weight 元信息进行平滑加权轮询
+	Weighted = "favorite_weighted"
+)
+
+func init() {
+	balancer.Register(base.NewBalancerBuilder(Weighted, &weightedPickerBuilder{}, base.Config{HealthCheck: true}))
+}
+
+type weightedPickerBuilder struct{}
+
+func (b *weightedPickerBuilder) Build(info base.PickerBuildInfo) balancer.Picker {
+	if len(info.ReadySCs) == 0 {
+		return base.NewErrPicker(balancer.ErrNoSubConnAvailable)
+	}
+
+	nodes := make([]*weightedNode, 0, len(info.ReadySCs))
+	for sc, sci := range info.ReadySCs {
+		nodes = append(nodes, &weightedNode{
+			sc:     sc,
+			weight: weightOf(sci.Address.Metadata),
+		})
+	}
+
+	return &weightedPicker{nodes: nodes}
+}
+
+type weightedNode struct {
+	sc            balancer.SubConn
+	weight        int
+	currentWeight int
+}
+
+// weightedPicker 平滑加权轮询, 与 nginx 的实现一致
+type weightedPicker struct {
+	mu    sync.Mutex
+	nodes []*weightedNode
+}
+
+func (p *weightedPicker) Pick(balancer.PickInfo) (balancer.PickResult, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	var (
+		total int
+		best  *weightedNode
+	)
+	for _, n := range p.nodes {
+		n.currentWeight += n.weight
+		total += n.weight
+		if best == nil || n.currentWeight > best.currentWeight {
+			best = n
+		}
+	}
+	best.currentWeight -= total
+
+	return balancer.PickResult{SubConn: best.sc}, nil
+}
+
+// weightOf 解析 etcd 中注册的元信息, 经 JSON 解码后为 map[string]any
+func weightOf(metadata any) int {
+	md, ok := metadata.(map[string]any)
+	if !ok {
+		return 1
+	}
+	w, ok := md["weight"].(float64)
+	if !ok || w < 1 {
+		return 1
+	}
+
+	return int(w)
+}
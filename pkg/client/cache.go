@@ -0,0 +1,115 @@
+package client
+
+import (
+	"sync"
+	"time"
+)
+
+// 删除记录的保留时长, 需大于一次查询的最长耗时
+const tombstoneTTL = time.Minute
+
+// localCache 带过期时间的本地缓存, 仅用于短 TTL 的读结果
+type localCache[K comparable, V any] struct {
+	ttl time.Duration
+
+	mu      sync.RWMutex
+	entries map[K]cacheEntry[V]
+	// 每次 Delete 递增, 用于识别删除前发起的查询
+	version    uint64
+	tombstones map[K]tombstone
+	stop       chan struct{}
+}
+
+type cacheEntry[V any] struct {
+	val      V
+	expireAt time.Time
+}
+
+type tombstone struct {
+	version   uint64
+	deletedAt time.Time
+}
+
+func newLocalCache[K comparable, V any](ttl time.Duration) *localCache[K, V] {
+	c := &localCache[K, V]{
+		ttl:        ttl,
+		entries:    make(map[K]cacheEntry[V]),
+		tombstones: make(map[K]tombstone),
+		stop:       make(chan struct{}),
+	}
+	go c.evictLoop()
+
+	return c
+}
+
+func (c *localCache[K, V]) Get(key K) (V, bool) {
+	c.mu.RLock()
+	e, ok := c.entries[key]
+	c.mu.RUnlock()
+
+	if !ok || time.Now().After(e.expireAt) {
+		var zero V
+		return zero, false
+	}
+	return e.val, true
+}
+
+// Version 查询前获取当前版本, 查询结果通过 Set 写入时传入
+func (c *localCache[K, V]) Version() uint64 {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.version
+}
+
+// Set version 为发起查询前的 Version, 查询期间 key 被 Delete 过时不写入, 避免旧值覆盖失效
+func (c *localCache[K, V]) Set(key K, val V, version uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if t, ok := c.tombstones[key]; ok && t.version > version {
+		return
+	}
+	c.entries[key] = cacheEntry[V]{val: val, expireAt: time.Now().Add(c.ttl)}
+}
+
+func (c *localCache[K, V]) Delete(key K) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.version++
+	c.tombstones[key] = tombstone{version: c.version, deletedAt: time.Now()}
+	delete(c.entries, key)
+}
+
+// evictLoop 定期清理过期数据, 避免长时间运行后内存增长
+func (c *localCache[K, V]) evictLoop() {
+	ticker := time.NewTicker(max(c.ttl, time.Second))
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+		case <-c.stop:
+			return
+		}
+
+		now := time.Now()
+		c.mu.Lock()
+		for k, e := range c.entries {
+			if now.After(e.expireAt) {
+				delete(c.entries, k)
+			}
+		}
+		for k, t := range c.tombstones {
+			if now.Sub(t.deletedAt) > tombstoneTTL {
+				delete(c.tombstones, k)
+			}
+		}
+		c.mu.Unlock()
+	}
+}
+
+func (c *localCache[K, V]) Close() {
+	close(c.stop)
+}
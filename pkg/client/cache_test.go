@@ -0,0 +1,53 @@
+package client
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLocalCache(t *testing.T) {
+	c := newLocalCache[string, int](50 * time.Millisecond)
+	defer c.Close()
+
+	_, ok := c.Get("a")
+	assert.False(t, ok)
+
+	c.Set("a", 1, c.Version())
+	v, ok := c.Get("a")
+	assert.True(t, ok)
+	assert.Equal(t, 1, v)
+
+	c.Delete("a")
+	_, ok = c.Get("a")
+	assert.False(t, ok)
+
+	// 过期后即使尚未被清理也不会返回
+	c.Set("b", 2, c.Version())
+	time.Sleep(60 * time.Millisecond)
+	_, ok = c.Get("b")
+	assert.False(t, ok)
+}
+
+func TestLocalCacheStaleSet(t *testing.T) {
+	c := newLocalCache[string, int](time.Minute)
+	defer c.Close()
+
+	// 查询发起后 key 被失效, 查询结果不应写入
+	version := c.Version()
+	c.Delete("a")
+	c.Set("a", 1, version)
+	_, ok := c.Get("a")
+	assert.False(t, ok)
+
+	// 失效后发起的查询正常写入, 且不影响其他 key
+	c.Set("a", 2, c.Version())
+	v, ok := c.Get("a")
+	assert.True(t, ok)
+	assert.Equal(t, 2, v)
+
+	c.Set("b", 3, version)
+	_, ok = c.Get("b")
+	assert.True(t, ok)
+}
@@ -0,0 +1,347 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	clientv3 "go.etcd.io/etcd/client/v3"
+	"go.etcd.io/etcd/client/v3/naming/resolver"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+
+	"github.com/crazyfrankie/favorite/api/rpc_gen/favorite"
+	"github.com/crazyfrankie/favorite/pkg/constants"
+)
+
+const (
+	// serviceName 与 pkg/registry 注册时使用的前缀保持一致
+	serviceName = "service/favorite"
+	// maxServerBatch 服务端批量接口单次允许的最大条数
+	maxServerBatch = 500
+	// defaultTimeout 默认的调用超时, 合并后的批量请求不属于任何调用方, 未设置超时时同样使用该值
+	defaultTimeout = time.Second
+)
+
+// idempotentMethods 可以安全重试的只读接口
+var idempotentMethods = []string{
+	"FavoriteList",
+	"IsFavorite",
+	"UserFavoriteCount",
+	"UserFavoritedCount",
+	"FavoriteCount",
+	"BizFavoriteUser",
+	"BatchIsFavorite",
+	"BatchFavoriteCount",
+}
+
+// Client FavoriteService 的客户端, 通过 etcd 发现服务实例
+type Client struct {
+	conn *grpc.ClientConn
+	rpc  favorite.FavoriteServiceClient
+
+	favLoader   *loader[favKey, bool]
+	countLoader *loader[countKey, int64]
+	favCache    *localCache[favKey, bool]
+	countCache  *localCache[countKey, int64]
+}
+
+type favKey struct {
+	uid   int64
+	biz   string
+	bizId int64
+}
+
+type countKey struct {
+	biz   string
+	bizId int64
+}
+
+func New(etcd *clientv3.Client, opts ...Option) (*Client, error) {
+	o := &options{
+		balancer:    RoundRobin,
+		timeout:     defaultTimeout,
+		maxAttempts: 3,
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	builder, err := resolver.NewBuilder(etcd)
+	if err != nil {
+		return nil, err
+	}
+	sc, err := serviceConfig(o)
+	if err != nil {
+		return nil, err
+	}
+
+	dialOpts := append([]grpc.DialOption{
+		grpc.WithResolvers(builder),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithDefaultServiceConfig(sc),
+		grpc.WithStatsHandler(otelgrpc.NewClientHandler()),
+		grpc.WithChainUnaryInterceptor(timeoutInterceptor(o.timeout)),
+	}, o.dialOpts...)
+
+	conn, err := grpc.NewClient("etcd:///"+serviceName, dialOpts...)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &Client{
+		conn: conn,
+		rpc:  favorite.NewFavoriteServiceClient(conn),
+	}
+	if o.batchWait > 0 {
+		if o.maxBatch <= 0 {
+			o.maxBatch = 100
+		}
+		// 超过服务端上限的批次会被整批拒绝
+		o.maxBatch = min(o.maxBatch, maxServerBatch)
+		c.favLoader = newLoader(o.batchWait, o.maxBatch, o.timeout, c.batchIsFavorite)
+		c.countLoader = newLoader(o.batchWait, o.maxBatch, o.timeout, c.batchFavoriteCount)
+	}
+	if o.cacheTTL > 0 {
+		c.favCache = newLocalCache[favKey, bool](o.cacheTTL)
+		c.countCache = newLocalCache[countKey, int64](o.cacheTTL)
+	}
+
+	return c, nil
+}
+
+// Raw 返回生成的原始客户端, 用于调用未封装的接口
+func (c *Client) Raw() favorite.FavoriteServiceClient {
+	return c.rpc
+}
+
+func (c *Client) Close() error {
+	if c.favCache != nil {
+		c.favCache.Close()
+		c.countCache.Close()
+	}
+	return c.conn.Close()
+}
+
+// WithToken 为调用附带用户 token
+func WithToken(ctx context.Context, token string) context.Context {
+	return metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+token)
+}
+
+// Like 点赞
+func (c *Client) Like(ctx context.Context, uid int64, biz string, bizId int64) error {
+	_, err := c.rpc.FavoriteAction(ctx, &favorite.FavoriteActionRequest{
+		Biz:        biz,
+		BizId:      bizId,
+		ActionType: constants.FavoriteActionType,
+		UserId:     uid,
+	})
+	c.invalidate(uid, biz, bizId)
+	return err
+}
+
+// Unlike 取消点赞
+func (c *Client) Unlike(ctx context.Context, uid int64, biz string, bizId int64) error {
+	_, err := c.rpc.FavoriteAction(ctx, &favorite.FavoriteActionRequest{
+		Biz:        biz,
+		BizId:      bizId,
+		ActionType: constants.UnFavoriteActionType,
+		UserId:     uid,
+	})
+	c.invalidate(uid, biz, bizId)
+	return err
+}
+
+// IsFavorite 用户是否点赞了某个内容
+func (c *Client) IsFavorite(ctx context.Context, uid int64, biz string, bizId int64) (bool, error) {
+	key := favKey{uid: uid, biz: biz, bizId: bizId}
+	var version uint64
+	if c.favCache != nil {
+		if v, ok := c.favCache.Get(key); ok {
+			return v, nil
+		}
+		version = c.favCache.Version()
+	}
+
+	var (
+		fav bool
+		err error
+	)
+	if c.favLoader != nil {
+		fav, err = c.favLoader.Load(ctx, key)
+	} else {
+		var resp *favorite.IsFavoriteResponse
+		resp, err = c.rpc.IsFavorite(ctx, &favorite.IsFavoriteRequest{UserId: uid, Biz: biz, BizId: bizId})
+		fav = resp.GetFavorite()
+	}
+	if err != nil {
+		return false, err
+	}
+
+	if c.favCache != nil {
+		c.favCache.Set(key, fav, version)
+	}
+	return fav, nil
+}
+
+// FavoriteCount 获取单个内容的点赞数
+func (c *Client) FavoriteCount(ctx context.Context, biz string, bizId int64) (int64, error) {
+	key := countKey{biz: biz, bizId: bizId}
+	var version uint64
+	if c.countCache != nil {
+		if v, ok := c.countCache.Get(key); ok {
+			return v, nil
+		}
+		version = c.countCache.Version()
+	}
+
+	var (
+		count int64
+		err   error
+	)
+	if c.countLoader != nil {
+		count, err = c.countLoader.Load(ctx, key)
+	} else {
+		var resp *favorite.FavoriteCountResponse
+		resp, err = c.rpc.FavoriteCount(ctx, &favorite.FavoriteCountRequest{Biz: biz, BizId: bizId})
+		count = resp.GetCount()
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	if c.countCache != nil {
+		c.countCache.Set(key, count, version)
+	}
+	return count, nil
+}
+
+// FavoriteList 获取用户的点赞列表, 元素格式为 "{biz}:{bizId}"
+func (c *Client) FavoriteList(ctx context.Context, uid int64) ([]string, error) {
+	resp, err := c.rpc.FavoriteList(ctx, &favorite.FavoriteListRequest{UserId: uid})
+	if err != nil {
+		return nil, err
+	}
+	return resp.GetLists(), nil
+}
+
+// BizFavoriteUser 获取内容的点赞用户
+func (c *Client) BizFavoriteUser(ctx context.Context, biz string, bizId int64) ([]int64, error) {
+	resp, err := c.rpc.BizFavoriteUser(ctx, &favorite.BizFavoriteUserRequest{Biz: biz, BizId: bizId})
+	if err != nil {
+		return nil, err
+	}
+	return resp.GetUserId(), nil
+}
+
+// UserFavoriteCount 获取用户的点赞总数
+func (c *Client) UserFavoriteCount(ctx context.Context, uid int64) (int64, error) {
+	resp, err := c.rpc.UserFavoriteCount(ctx, &favorite.UserFavoriteCountRequest{UserId: uid})
+	if err != nil {
+		return 0, err
+	}
+	return resp.GetCount(), nil
+}
+
+func (c *Client) batchIsFavorite(ctx context.Context, keys []favKey) ([]bool, error) {
+	items := make([]*favorite.IsFavoriteRequest, 0, len(keys))
+	for _, k := range keys {
+		items = append(items, &favorite.IsFavoriteRequest{UserId: k.uid, Biz: k.biz, BizId: k.bizId})
+	}
+
+	resp, err := c.rpc.BatchIsFavorite(ctx, &favorite.BatchIsFavoriteRequest{Items: items})
+	if err != nil {
+		return nil, err
+	}
+	if len(resp.GetFavorites()) != len(keys) {
+		return nil, fmt.Errorf("unexpected batch size: want %d, got %d", len(keys), len(resp.GetFavorites()))
+	}
+	return resp.GetFavorites(), nil
+}
+
+func (c *Client) batchFavoriteCount(ctx context.Context, keys []countKey) ([]int64, error) {
+	items := make([]*favorite.FavoriteCountRequest, 0, len(keys))
+	for _, k := range keys {
+		items = append(items, &favorite.FavoriteCountRequest{Biz: k.biz, BizId: k.bizId})
+	}
+
+	resp, err := c.rpc.BatchFavoriteCount(ctx, &favorite.BatchFavoriteCountRequest{Items: items})
+	if err != nil {
+		return nil, err
+	}
+	if len(resp.GetCounts()) != len(keys) {
+		return nil, fmt.Errorf("unexpected batch size: want %d, got %d", len(keys), len(resp.GetCounts()))
+	}
+	return resp.GetCounts(), nil
+}
+
+// invalidate 本客户端点赞状态变化后失效相关的本地缓存
+func (c *Client) invalidate(uid int64, biz string, bizId int64) {
+	if c.favCache == nil {
+		return
+	}
+	c.favCache.Delete(favKey{uid: uid, biz: biz, bizId: bizId})
+	c.countCache.Delete(countKey{biz: biz, bizId: bizId})
+}
+
+// timeoutInterceptor 调用方未设置 deadline 时使用默认超时
+func timeoutInterceptor(timeout time.Duration) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		if _, ok := ctx.Deadline(); !ok && timeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, timeout)
+			defer cancel()
+		}
+		return invoker(ctx, method, req, reply, cc, opts...)
+	}
+}
+
+// serviceConfig 生成负载均衡及只读接口的重试策略
+func serviceConfig(o *options) (string, error) {
+	type name struct {
+		Service string `json:"service"`
+		Method  string `json:"method"`
+	}
+	type retryPolicy struct {
+		MaxAttempts          int      `json:"maxAttempts"`
+		InitialBackoff       string   `json:"initialBackoff"`
+		MaxBackoff           string   `json:"maxBackoff"`
+		BackoffMultiplier    float64  `json:"backoffMultiplier"`
+		RetryableStatusCodes []string `json:"retryableStatusCodes"`
+	}
+	type methodConfig struct {
+		Name        []name       `json:"name"`
+		RetryPolicy *retryPolicy `json:"retryPolicy,omitempty"`
+	}
+
+	names := make([]name, 0, len(idempotentMethods))
+	for _, m := range idempotentMethods {
+		names = append(names, name{Service: favorite.FavoriteService_ServiceDesc.ServiceName, Method: m})
+	}
+
+	cfg := map[string]any{
+		"loadBalancingConfig": []map[string]any{{o.balancer: struct{}{}}},
+	}
+	if o.maxAttempts > 1 {
+		cfg["methodConfig"] = []methodConfig{{
+			Name: names,
+			RetryPolicy: &retryPolicy{
+				MaxAttempts:          o.maxAttempts,
+				InitialBackoff:       "0.05s",
+				MaxBackoff:           "1s",
+				BackoffMultiplier:    2,
+				RetryableStatusCodes: []string{"UNAVAILABLE"},
+			},
+		}}
+	}
+
+	b, err := json.Marshal(cfg)
+	if err != nil {
+		return "", fmt.Errorf("failed to build service config: %w", err)
+	}
+
+	return string(b), nil
+}
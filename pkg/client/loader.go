@@ -0,0 +1,151 @@
+package client
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc/metadata"
+)
+
+// loader 将短时间窗口内的并发单条查询合并为一次批量查询,
+// 只合并 outgoing metadata 相同的调用, 不同 token 的调用不会出现在同一个批量请求中
+type loader[K comparable, V any] struct {
+	wait     time.Duration
+	maxBatch int
+	timeout  time.Duration
+	// fetch 返回的结果需与 keys 一一对应
+	fetch func(ctx context.Context, keys []K) ([]V, error)
+
+	mu sync.Mutex
+	// 按 metadata 分组的待发出批次
+	pending map[string]*batch[K, V]
+}
+
+type batch[K comparable, V any] struct {
+	group string
+	// 批量请求沿用第一个调用方的 metadata 及 trace
+	md   metadata.MD
+	span trace.SpanContext
+	keys []K
+	idx  map[K]int
+	done chan struct{}
+	res  []V
+	err  error
+}
+
+// newLoader timeout 为批量查询的超时, 不大于 0 时使用 defaultTimeout, 避免批次无限期等待
+func newLoader[K comparable, V any](wait time.Duration, maxBatch int, timeout time.Duration,
+	fetch func(ctx context.Context, keys []K) ([]V, error)) *loader[K, V] {
+	if timeout <= 0 {
+		timeout = defaultTimeout
+	}
+
+	return &loader[K, V]{
+		wait:     wait,
+		maxBatch: maxBatch,
+		timeout:  timeout,
+		fetch:    fetch,
+		pending:  make(map[string]*batch[K, V]),
+	}
+}
+
+func (l *loader[K, V]) Load(ctx context.Context, key K) (V, error) {
+	b, i := l.enqueue(ctx, key)
+
+	select {
+	case <-b.done:
+		var zero V
+		if b.err != nil {
+			return zero, b.err
+		}
+		return b.res[i], nil
+	case <-ctx.Done():
+		var zero V
+		return zero, ctx.Err()
+	}
+}
+
+func (l *loader[K, V]) enqueue(ctx context.Context, key K) (*batch[K, V], int) {
+	md, _ := metadata.FromOutgoingContext(ctx)
+	group := metadataGroup(md)
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	b := l.pending[group]
+	if b == nil {
+		b = &batch[K, V]{
+			group: group,
+			md:    md.Copy(),
+			span:  trace.SpanContextFromContext(ctx),
+			idx:   make(map[K]int),
+			done:  make(chan struct{}),
+		}
+		l.pending[group] = b
+		time.AfterFunc(l.wait, func() { l.dispatch(b) })
+	}
+
+	// 同一批次内的相同 key 只查询一次
+	if i, ok := b.idx[key]; ok {
+		return b, i
+	}
+	i := len(b.keys)
+	b.keys = append(b.keys, key)
+	b.idx[key] = i
+
+	if len(b.keys) >= l.maxBatch {
+		delete(l.pending, group)
+		go l.run(b)
+	}
+
+	return b, i
+}
+
+// dispatch 等待时间到达后发出批次, 已因攒满而提前发出的批次直接忽略
+func (l *loader[K, V]) dispatch(b *batch[K, V]) {
+	l.mu.Lock()
+	if l.pending[b.group] != b {
+		l.mu.Unlock()
+		return
+	}
+	delete(l.pending, b.group)
+	l.mu.Unlock()
+
+	l.run(b)
+}
+
+func (l *loader[K, V]) run(b *batch[K, V]) {
+	// 批次由多个调用方共享, 不能使用任何一个调用方的 ctx, 避免其取消影响其他调用方
+	ctx := metadata.NewOutgoingContext(context.Background(), b.md)
+	ctx = trace.ContextWithSpanContext(ctx, b.span)
+	ctx, cancel := context.WithTimeout(ctx, l.timeout)
+	defer cancel()
+
+	b.res, b.err = l.fetch(ctx, b.keys)
+	close(b.done)
+}
+
+// metadataGroup 将 metadata 编码为分组 key, 内容相同的 metadata 得到相同的 key
+func metadataGroup(md metadata.MD) string {
+	keys := make([]string, 0, len(md))
+	for k := range md {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+
+	var sb strings.Builder
+	for _, k := range keys {
+		sb.WriteString(k)
+		for _, v := range md[k] {
+			sb.WriteByte(0)
+			sb.WriteString(v)
+		}
+		sb.WriteByte('\n')
+	}
+
+	return sb.String()
+}
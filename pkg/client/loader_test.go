@@ -0,0 +1,145 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/metadata"
+)
+
+func TestLoaderMergesBatch(t *testing.T) {
+	var (
+		mu      sync.Mutex
+		batches [][]int
+	)
+	l := newLoader(20*time.Millisecond, 100, time.Second, func(ctx context.Context, keys []int) ([]int, error) {
+		mu.Lock()
+		batches = append(batches, append([]int(nil), keys...))
+		mu.Unlock()
+
+		res := make([]int, len(keys))
+		for i, k := range keys {
+			res[i] = k * 10
+		}
+		return res, nil
+	})
+
+	keys := []int{1, 2, 3, 2, 1}
+	res := make([]int, len(keys))
+	var wg sync.WaitGroup
+	for i, k := range keys {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			v, err := l.Load(context.Background(), k)
+			assert.NoError(t, err)
+			res[i] = v
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, []int{10, 20, 30, 20, 10}, res)
+	// 并发查询合并为一次批量查询, 相同 key 只查询一次
+	require.Len(t, batches, 1)
+	assert.ElementsMatch(t, []int{1, 2, 3}, batches[0])
+}
+
+func TestLoaderMaxBatch(t *testing.T) {
+	var calls atomic.Int32
+	l := newLoader(time.Hour, 2, time.Second, func(ctx context.Context, keys []int) ([]int, error) {
+		calls.Add(1)
+		assert.Len(t, keys, 2)
+		return keys, nil
+	})
+
+	// 攒满 maxBatch 后立即发出, 不等待 wait
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			v, err := l.Load(context.Background(), i)
+			assert.NoError(t, err)
+			assert.Equal(t, i, v)
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, int32(2), calls.Load())
+}
+
+func TestLoaderError(t *testing.T) {
+	errFetch := errors.New("fetch failed")
+	l := newLoader(time.Millisecond, 100, time.Second, func(ctx context.Context, keys []int) ([]int, error) {
+		return nil, errFetch
+	})
+
+	_, err := l.Load(context.Background(), 1)
+	assert.ErrorIs(t, err, errFetch)
+}
+
+func TestLoaderTimeout(t *testing.T) {
+	// 未设置超时时批量查询使用 defaultTimeout, 而不是无限期等待
+	l := newLoader(time.Millisecond, 100, 0, func(ctx context.Context, keys []int) ([]int, error) {
+		deadline, ok := ctx.Deadline()
+		assert.True(t, ok)
+		assert.WithinDuration(t, time.Now().Add(defaultTimeout), deadline, 100*time.Millisecond)
+		return keys, nil
+	})
+
+	_, err := l.Load(context.Background(), 1)
+	assert.NoError(t, err)
+}
+
+func TestLoaderCallerCanceled(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+	l := newLoader(time.Millisecond, 100, time.Second, func(ctx context.Context, keys []int) ([]int, error) {
+		<-release
+		return keys, nil
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	_, err := l.Load(ctx, 1)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestLoaderGroupsByMetadata(t *testing.T) {
+	var (
+		mu      sync.Mutex
+		batches = make(map[string][]int)
+	)
+	l := newLoader(20*time.Millisecond, 100, time.Second, func(ctx context.Context, keys []int) ([]int, error) {
+		md, _ := metadata.FromOutgoingContext(ctx)
+		mu.Lock()
+		batches[strings.Join(md.Get("authorization"), ",")] = append([]int(nil), keys...)
+		mu.Unlock()
+		return keys, nil
+	})
+
+	// 不同 token 的调用分别发出, 批量请求携带各自的 token
+	tokens := []string{"a", "b", "a", "b"}
+	var wg sync.WaitGroup
+	for i, token := range tokens {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ctx := metadata.AppendToOutgoingContext(context.Background(), "authorization", token)
+			_, err := l.Load(ctx, i)
+			assert.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+
+	require.Len(t, batches, 2)
+	assert.ElementsMatch(t, []int{0, 2}, batches["a"])
+	assert.ElementsMatch(t, []int{1, 3}, batches["b"])
+}
@@ -0,0 +1,64 @@
+package client
+
+import (
+	"time"
+
+	"google.golang.org/grpc"
+)
+
+type options struct {
+	// 负载均衡策略, RoundRobin 或 Weighted
+	balancer string
+	// 调用方未设置 deadline 时的默认超时
+	timeout time.Duration
+	// 幂等读接口的最大尝试次数, 包含首次调用
+	maxAttempts int
+	dialOpts    []grpc.DialOption
+
+	// 合并 IsFavorite/FavoriteCount 请求的等待窗口, 为 0 表示不合并
+	batchWait time.Duration
+	maxBatch  int
+	// 本地缓存时长, 为 0 表示不缓存
+	cacheTTL time.Duration
+}
+
+type Option func(*options)
+
+func WithBalancer(name string) Option {
+	return func(o *options) {
+		o.balancer = name
+	}
+}
+
+func WithTimeout(timeout time.Duration) Option {
+	return func(o *options) {
+		o.timeout = timeout
+	}
+}
+
+func WithMaxAttempts(n int) Option {
+	return func(o *options) {
+		o.maxAttempts = n
+	}
+}
+
+// WithBatching 将 wait 时间内的并发 IsFavorite/FavoriteCount 调用合并为批量请求, 单批最多 maxBatch 条
+func WithBatching(wait time.Duration, maxBatch int) Option {
+	return func(o *options) {
+		o.batchWait = wait
+		o.maxBatch = maxBatch
+	}
+}
+
+// WithLocalCache 在本地缓存 IsFavorite/FavoriteCount 的结果, 通过本客户端点赞或取消点赞时会失效对应缓存
+func WithLocalCache(ttl time.Duration) Option {
+	return func(o *options) {
+		o.cacheTTL = ttl
+	}
+}
+
+func WithDialOptions(opts ...grpc.DialOption) Option {
+	return func(o *options) {
+		o.dialOpts = append(o.dialOpts, opts...)
+	}
+}
@@ -4,3 +4,32 @@ const (
 	FavoriteActionType   = 1 // 点赞
 	UnFavoriteActionType = 2 // 取消点赞
 )
+
+const (
+	FavoriteStatusUnliked = 0 // 已取消点赞
+	FavoriteStatusLiked   = 1 // 已点赞
+)
+
+const (
+	PrivacyPublic    = 0 // 所有人可见
+	PrivacyFollowers = 1 // 仅关注者可见
+	PrivacyPrivate   = 2 // 仅自己可见
+)
+
+const (
+	EraseStatusPending = 0 // 等待执行
+	EraseStatusRunning = 1 // 执行中
+	EraseStatusDone    = 2 // 已完成
+	EraseStatusFailed  = 3 // 执行失败, 等待重试
+)
+
+const (
+	ExportFormatProto = 0 // protobuf 消息
+	ExportFormatJSONL = 1 // JSON Lines
+	ExportFormatCSV   = 2 // CSV
+)
+
+const (
+	HistoryGranularityHour = 1 // 按小时
+	HistoryGranularityDay  = 2 // 按天
+)
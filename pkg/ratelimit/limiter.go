@@ -0,0 +1,72 @@
+package ratelimit
+
+import (
+	"context"
+	_ "embed"
+	"fmt"
+	"math/rand/v2"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+//go:embed lua/slide_window.lua
+var luaSlideWindow string
+
+// Rule 限流规则, 在 Window 时间内最多允许 Rate 次请求
+type Rule struct {
+	Window time.Duration
+	Rate   int
+}
+
+// Key 限流对象及其规则
+type Key struct {
+	Key  string
+	Rule Rule
+}
+
+type Limiter interface {
+	// Limit 判断 keys 是否需要限流, 任一 key 超限时本次请求不计入任何 key;
+	// 返回超限 key 在 keys 中的下标及需要等待的时间, 未限流时下标为 -1
+	Limit(ctx context.Context, keys ...Key) (int, time.Duration, error)
+}
+
+// RedisSlidingWindowLimiter 基于 Redis ZSET 的滑动窗口限流器
+type RedisSlidingWindowLimiter struct {
+	cmd redis.Cmdable
+}
+
+func NewRedisSlidingWindowLimiter(cmd redis.Cmdable) Limiter {
+	return &RedisSlidingWindowLimiter{cmd: cmd}
+}
+
+func (r *RedisSlidingWindowLimiter) Limit(ctx context.Context, keys ...Key) (int, time.Duration, error) {
+	now := time.Now().UnixMilli()
+	member := fmt.Sprintf("%d:%d", now, rand.Int64())
+
+	// 未配置规则的 key 不参与限流, idx 记录脚本中的序号对应的下标
+	names := make([]string, 0, len(keys))
+	idx := make([]int, 0, len(keys))
+	args := []any{now, member}
+	for i, k := range keys {
+		if k.Rule.Rate <= 0 || k.Rule.Window <= 0 {
+			continue
+		}
+		names = append(names, k.Key)
+		idx = append(idx, i)
+		args = append(args, k.Rule.Window.Milliseconds(), k.Rule.Rate)
+	}
+	if len(names) == 0 {
+		return -1, 0, nil
+	}
+
+	res, err := r.cmd.Eval(ctx, luaSlideWindow, names, args...).Int64Slice()
+	if err != nil {
+		return -1, 0, err
+	}
+	if len(res) != 2 || res[0] <= 0 || int(res[0]) > len(idx) {
+		return -1, 0, nil
+	}
+
+	return idx[res[0]-1], time.Duration(res[1]) * time.Millisecond, nil
+}
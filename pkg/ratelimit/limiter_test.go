@@ -0,0 +1,75 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestLimiter(t *testing.T) (Limiter, *miniredis.Miniredis) {
+	t.Helper()
+
+	mr := miniredis.RunT(t)
+	cmd := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { _ = cmd.Close() })
+
+	return NewRedisSlidingWindowLimiter(cmd), mr
+}
+
+func TestRedisSlidingWindowLimiter(t *testing.T) {
+	ctx := context.Background()
+	l, _ := newTestLimiter(t)
+
+	user := Key{Key: "limit:user:1", Rule: Rule{Window: time.Minute, Rate: 2}}
+	for i := 0; i < 2; i++ {
+		idx, wait, err := l.Limit(ctx, user)
+		require.NoError(t, err)
+		assert.Equal(t, -1, idx)
+		assert.Zero(t, wait)
+	}
+
+	idx, wait, err := l.Limit(ctx, user)
+	require.NoError(t, err)
+	assert.Equal(t, 0, idx)
+	assert.Greater(t, wait, time.Duration(0))
+	assert.LessOrEqual(t, wait, time.Minute)
+}
+
+func TestRedisSlidingWindowLimiterMultiKey(t *testing.T) {
+	ctx := context.Background()
+	l, mr := newTestLimiter(t)
+
+	ip := Key{Key: "limit:ip:1", Rule: Rule{Window: time.Minute, Rate: 10}}
+	user := Key{Key: "limit:user:1", Rule: Rule{Window: time.Minute, Rate: 1}}
+	// 未配置规则的 key 不参与限流, 下标仍按传入顺序返回
+	none := Key{Key: "limit:none"}
+
+	idx, _, err := l.Limit(ctx, none, ip, user)
+	require.NoError(t, err)
+	assert.Equal(t, -1, idx)
+
+	idx, _, err = l.Limit(ctx, none, ip, user)
+	require.NoError(t, err)
+	assert.Equal(t, 2, idx)
+
+	// 被拒绝的请求不消耗其他 key 的配额
+	members, err := mr.ZMembers(ip.Key)
+	require.NoError(t, err)
+	assert.Len(t, members, 1)
+	assert.False(t, mr.Exists(none.Key))
+}
+
+func TestRedisSlidingWindowLimiterNoRule(t *testing.T) {
+	l, mr := newTestLimiter(t)
+
+	idx, wait, err := l.Limit(context.Background(), Key{Key: "limit:none"})
+	require.NoError(t, err)
+	assert.Equal(t, -1, idx)
+	assert.Zero(t, wait)
+	assert.Empty(t, mr.Keys())
+}
@@ -0,0 +1,32 @@
+-- 限流对象, 可同时传入多个, 全部未超限时才记录本次请求
+-- ARGV[1]: 当前时间戳(毫秒)
+local now = tonumber(ARGV[1])
+-- ARGV[2]: 本次请求的唯一标识
+local member = ARGV[2]
+-- ARGV[2i+1], ARGV[2i+2]: 第 i 个 key 的窗口大小(毫秒)及窗口内允许的请求数
+
+-- 先检查所有 key, 任一超限时不在任何 key 上记录, 避免被拒绝的请求消耗其他维度的配额
+for i, key in ipairs(KEYS) do
+    local window = tonumber(ARGV[2 * i + 1])
+    local threshold = tonumber(ARGV[2 * i + 2])
+
+    -- 移除窗口起始时间之前的记录
+    redis.call('ZREMRANGEBYSCORE', key, '-inf', now - window)
+    local cnt = redis.call('ZCARD', key)
+    if cnt >= threshold then
+        -- 执行限流, 返回超限 key 的序号及最早一条记录离开窗口还需要的毫秒数
+        local oldest = redis.call('ZRANGE', key, 0, 0, 'WITHSCORES')
+        local wait = tonumber(oldest[2]) + window - now
+        if wait < 1 then
+            wait = 1
+        end
+        return { i, wait }
+    end
+end
+
+for i, key in ipairs(KEYS) do
+    local window = tonumber(ARGV[2 * i + 1])
+    redis.call('ZADD', key, now, member)
+    redis.call('PEXPIRE', key, window)
+end
+return { 0, 0 }
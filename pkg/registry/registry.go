@@ -2,38 +2,83 @@ package registry
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"github.com/crazyfrankie/favorite/internal/config"
+	"net"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	clientv3 "go.etcd.io/etcd/client/v3"
 	"go.etcd.io/etcd/client/v3/naming/endpoints"
 	"go.uber.org/zap"
+
+	"github.com/crazyfrankie/favorite/internal/config"
+)
+
+const (
+	ServiceName = "service/favorite"
+
+	defaultTTL = 180
 )
 
+// Metadata 随服务地址一起注册的元信息, 供客户端做路由与负载均衡
+type Metadata struct {
+	Version string `json:"version"`
+	Zone    string `json:"zone"`
+	Weight  int    `json:"weight"`
+}
+
 type ServiceRegistry struct {
 	client     *clientv3.Client
 	em         endpoints.Manager
 	addr       string
 	serviceKey string
+	ttl        int64
+	meta       Metadata
 	mu         sync.Mutex
 	leaseID    clientv3.LeaseID
+	registered atomic.Bool
+
+	// 续约协程的生命周期, 由 mu 保护
+	cancel context.CancelFunc
+	done   chan struct{}
+	closed bool
 }
 
 func NewServiceRegistry(cli *clientv3.Client) (*ServiceRegistry, error) {
-	addr := "localhost" + config.GetConf().Server.Port
-	serviceKey := "service/favorite/" + addr
-	em, err := endpoints.NewManager(cli, "service/favorite")
+	conf := config.GetConf()
+
+	addr, err := advertiseAddr(conf.Registry.AdvertiseAddr, conf.Server.Port)
 	if err != nil {
 		return nil, err
 	}
+	em, err := endpoints.NewManager(cli, ServiceName)
+	if err != nil {
+		return nil, err
+	}
+
+	ttl := conf.Registry.TTL
+	if ttl <= 0 {
+		ttl = defaultTTL
+	}
+	weight := conf.Registry.Weight
+	if weight <= 0 {
+		weight = 1
+	}
 
 	return &ServiceRegistry{
 		client:     cli,
 		em:         em,
 		addr:       addr,
-		serviceKey: serviceKey,
+		serviceKey: ServiceName + "/" + addr,
+		ttl:        ttl,
+		meta: Metadata{
+			Version: conf.Registry.Version,
+			Zone:    conf.Registry.Zone,
+			Weight:  weight,
+		},
 	}, nil
 }
 
@@ -41,52 +86,122 @@ func (r *ServiceRegistry) Register() error {
 	ctx, cancel := context.WithTimeout(context.Background(), time.Second*10)
 	defer cancel()
 
-	leaseResp, err := r.client.Grant(ctx, 180)
-	if err != nil {
+	if err := r.register(ctx); err != nil {
 		return err
 	}
 
+	// 开始续约, 已注销时不再续约
 	r.mu.Lock()
-	r.leaseID = leaseResp.ID
-	r.mu.Unlock()
+	defer r.mu.Unlock()
+	if r.closed {
+		return errors.New("registry already unregistered")
+	}
+	kaCtx, kaCancel := context.WithCancel(context.Background())
+	r.cancel = kaCancel
+	r.done = make(chan struct{})
+	go r.keepAlive(kaCtx, r.done)
+
+	return nil
+}
+
+// register 申请新租约并写入服务地址
+func (r *ServiceRegistry) register(ctx context.Context) error {
+	leaseResp, err := r.client.Grant(ctx, r.ttl)
+	if err != nil {
+		return err
+	}
 
-	ctx, cancel = context.WithTimeout(context.Background(), time.Second)
-	defer cancel()
 	if err := r.em.AddEndpoint(ctx, r.serviceKey,
-		endpoints.Endpoint{Addr: r.addr}, clientv3.WithLease(leaseResp.ID)); err != nil {
+		endpoints.Endpoint{Addr: r.addr, Metadata: r.meta}, clientv3.WithLease(leaseResp.ID)); err != nil {
 		return err
 	}
 
-	// 开始续约
-	go r.KeepAlive()
+	r.mu.Lock()
+	r.leaseID = leaseResp.ID
+	r.mu.Unlock()
+	r.registered.Store(true)
 
 	return nil
 }
 
-func (r *ServiceRegistry) KeepAlive() {
-	ctx, cancel := context.WithCancel(context.Background())
-	defer cancel()
+// keepAlive 持续续约, 租约丢失(如 etcd 长时间不可达导致过期)时重新申请租约并注册
+func (r *ServiceRegistry) keepAlive(ctx context.Context, done chan struct{}) {
+	defer close(done)
 
-	ch, err := r.client.KeepAlive(ctx, r.leaseID)
-	if err != nil {
-		zap.L().Error("KeepAlive failed", zap.Error(err))
+	for {
+		ch, err := r.client.KeepAlive(ctx, r.currentLease())
+		if err != nil {
+			zap.L().Error("KeepAlive failed", zap.Error(err))
+		} else {
+			// channel 关闭说明租约已失效或 ctx 已取消
+			for range ch {
+			}
+		}
+		r.registered.Store(false)
+
+		if ctx.Err() != nil {
+			return
+		}
+		zap.L().Warn("lease lost, re-registering", zap.String("key", r.serviceKey))
+
+		if !r.reRegister(ctx) {
+			return
+		}
+		zap.L().Info("service re-registered", zap.String("key", r.serviceKey))
 	}
+}
 
+// reRegister 以指数退避重试注册, ctx 取消时返回 false
+func (r *ServiceRegistry) reRegister(ctx context.Context) bool {
+	backoff := time.Second
 	for {
 		select {
-		case _, ok := <-ch:
-			if !ok {
-				zap.L().Info("KeepAlive channel closed")
-				return
-			}
-			fmt.Println("Lease renewed")
 		case <-ctx.Done():
-			return
+			return false
+		case <-time.After(backoff):
+		}
+
+		regCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+		err := r.register(regCtx)
+		cancel()
+		if err == nil {
+			return true
 		}
+
+		zap.L().Error("re-register failed", zap.Error(err))
+		backoff = min(backoff*2, 30*time.Second)
 	}
 }
 
+func (r *ServiceRegistry) currentLease() clientv3.LeaseID {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.leaseID
+}
+
+// Registered 服务当前是否已注册且租约有效
+func (r *ServiceRegistry) Registered() bool {
+	return r.registered.Load()
+}
+
+// Addr 注册到 etcd 的服务地址
+func (r *ServiceRegistry) Addr() string {
+	return r.addr
+}
+
 func (r *ServiceRegistry) UnRegister() error {
+	// 先停止续约, 避免注销后又被重新注册
+	r.mu.Lock()
+	r.closed = true
+	cancel, done := r.cancel, r.done
+	r.mu.Unlock()
+	if cancel != nil {
+		cancel()
+		<-done
+	}
+	r.registered.Store(false)
+
 	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
 	defer cancel()
 
@@ -94,11 +209,55 @@ func (r *ServiceRegistry) UnRegister() error {
 		return fmt.Errorf("failed to delete endpoint: %v", err)
 	}
 
-	leaseID := r.leaseID
-
-	if _, err := r.client.Revoke(ctx, leaseID); err != nil {
+	if _, err := r.client.Revoke(ctx, r.currentLease()); err != nil {
 		return fmt.Errorf("failed to revoke lease: %v", err)
 	}
 
 	return nil
 }
+
+// advertiseAddr 优先使用配置的地址, 未配置时探测本机第一个非回环 IPv4 地址
+func advertiseAddr(configured, port string) (string, error) {
+	if configured != "" {
+		if _, _, err := net.SplitHostPort(configured); err == nil {
+			return configured, nil
+		}
+		return configured + port, nil
+	}
+
+	ip, err := localIP()
+	if err != nil {
+		return "", err
+	}
+
+	return ip + port, nil
+}
+
+func localIP() (string, error) {
+	ifaces, err := net.Interfaces()
+	if err != nil {
+		return "", err
+	}
+
+	for _, iface := range ifaces {
+		if iface.Flags&net.FlagUp == 0 || iface.Flags&net.FlagLoopback != 0 {
+			continue
+		}
+		// 跳过常见的容器网桥
+		if strings.HasPrefix(iface.Name, "docker") || strings.HasPrefix(iface.Name, "br-") {
+			continue
+		}
+
+		addrs, err := iface.Addrs()
+		if err != nil {
+			continue
+		}
+		for _, a := range addrs {
+			if ipNet, ok := a.(*net.IPNet); ok && ipNet.IP.To4() != nil {
+				return ipNet.IP.String(), nil
+			}
+		}
+	}
+
+	return "", errors.New("no available ipv4 address, set registry.advertiseAddr instead")
+}
@@ -0,0 +1,98 @@
+package rpc
+
+import (
+	"context"
+	"strings"
+
+	middleware "github.com/grpc-ecosystem/go-grpc-middleware/v2"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/crazyfrankie/favorite/api/rpc_gen/favorite"
+	"github.com/crazyfrankie/favorite/pkg/auth"
+)
+
+// privileged 仅允许特定角色调用的接口, 其余接口允许匿名调用, 由各接口自行校验调用方
+var privileged = map[string][]string{
+	favorite.FavoriteService_EraseUserFavorites_FullMethodName:    {auth.RoleAdmin},
+	favorite.FavoriteService_QueryActionLogs_FullMethodName:       {auth.RoleAdmin},
+	favorite.FavoriteService_FavoriteStateAt_FullMethodName:       {auth.RoleAdmin},
+	favorite.FavoriteService_ListFlaggedFavorites_FullMethodName:  {auth.RoleAdmin},
+	favorite.FavoriteService_PurgeFlaggedFavorites_FullMethodName: {auth.RoleAdmin},
+	favorite.FavoriteService_ImportFavorites_FullMethodName:       {auth.RoleAdmin},
+	// 删除内容会清除其全部点赞数据, 只能由内容服务或管理端调用
+	favorite.FavoriteService_PurgeContent_FullMethodName: {auth.RoleService, auth.RoleAdmin},
+	// 内容归属决定创作者获赞数, 只能由内容服务登记
+	favorite.FavoriteService_RegisterContentOwner_FullMethodName: {auth.RoleService, auth.RoleAdmin},
+	favorite.JobAdminService_ListJobs_FullMethodName:             {auth.RoleAdmin},
+	favorite.JobAdminService_PauseJob_FullMethodName:             {auth.RoleAdmin},
+	favorite.JobAdminService_ResumeJob_FullMethodName:            {auth.RoleAdmin},
+	favorite.JobAdminService_TriggerJob_FullMethodName:           {auth.RoleAdmin},
+}
+
+// authInterceptor 解析调用方身份, 未携带 token 的请求视为匿名调用
+func authInterceptor(secret []byte) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		ctx, err := authenticate(ctx, secret)
+		if err != nil {
+			return nil, err
+		}
+		if err := authorize(ctx, info.FullMethod); err != nil {
+			return nil, err
+		}
+
+		return handler(ctx, req)
+	}
+}
+
+// authStreamInterceptor 流式接口的身份解析, 规则同 authInterceptor
+func authStreamInterceptor(secret []byte) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, err := authenticate(ss.Context(), secret)
+		if err != nil {
+			return err
+		}
+		if err := authorize(ctx, info.FullMethod); err != nil {
+			return err
+		}
+
+		wrapped := middleware.WrapServerStream(ss)
+		wrapped.WrappedContext = ctx
+
+		return handler(srv, wrapped)
+	}
+}
+
+func authenticate(ctx context.Context, secret []byte) (context.Context, error) {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ctx, nil
+	}
+	vals := md.Get("authorization")
+	if len(vals) == 0 || vals[0] == "" {
+		return ctx, nil
+	}
+
+	token := strings.TrimPrefix(vals[0], "Bearer ")
+	id, err := auth.ParseToken(token, secret)
+	if err != nil {
+		return nil, status.Errorf(codes.Unauthenticated, "invalid token: %v", err)
+	}
+
+	return auth.WithCaller(ctx, id), nil
+}
+
+// authorize 校验 privileged 中接口的调用方角色
+func authorize(ctx context.Context, method string) error {
+	roles, ok := privileged[method]
+	if !ok || auth.HasRole(ctx, roles...) {
+		return nil
+	}
+	if _, ok := auth.IdentityFromContext(ctx); !ok {
+		return status.Errorf(codes.Unauthenticated, "%s requires authentication", method)
+	}
+
+	return status.Errorf(codes.PermissionDenied, "%s requires role %v", method, roles)
+}
@@ -0,0 +1,33 @@
+package rpc
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/crazyfrankie/favorite/api/rpc_gen/favorite"
+	"github.com/crazyfrankie/favorite/pkg/auth"
+)
+
+func TestAuthorizePurgeContent(t *testing.T) {
+	method := favorite.FavoriteService_PurgeContent_FullMethodName
+
+	tests := []struct {
+		name string
+		ctx  context.Context
+		code codes.Code
+	}{
+		{name: "anonymous", ctx: context.Background(), code: codes.Unauthenticated},
+		{name: "user", ctx: auth.WithCaller(context.Background(), auth.Identity{UserId: 1}), code: codes.PermissionDenied},
+		{name: "service", ctx: auth.WithCaller(context.Background(), auth.Identity{Role: auth.RoleService}), code: codes.OK},
+		{name: "admin", ctx: auth.WithCaller(context.Background(), auth.Identity{Role: auth.RoleAdmin}), code: codes.OK},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.code, status.Code(authorize(tc.ctx, method)))
+		})
+	}
+}
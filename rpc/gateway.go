@@ -0,0 +1,298 @@
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+
+	"github.com/crazyfrankie/favorite/api/rpc_gen/favorite"
+	"github.com/crazyfrankie/favorite/pkg/constants"
+)
+
+// 单个请求体的最大长度
+const maxGatewayBody = 1 << 20
+
+var (
+	gatewayMarshaler   = protojson.MarshalOptions{UseProtoNames: true, EmitUnpopulated: true}
+	gatewayUnmarshaler = protojson.UnmarshalOptions{DiscardUnknown: true}
+)
+
+// Gateway 将 HTTP/JSON 请求转发到本机的 gRPC 服务,
+// 复用 gRPC 的拦截器链, 保证鉴权、限流与错误码和 gRPC 调用一致
+type Gateway struct {
+	conn   *grpc.ClientConn
+	client favorite.FavoriteServiceClient
+	mux    *http.ServeMux
+}
+
+// NewGateway addr 为 gRPC 服务的监听地址, 如 ":9091"
+func NewGateway(addr string) (*Gateway, error) {
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return nil, err
+	}
+	if host == "" {
+		host = "127.0.0.1"
+	}
+
+	conn, err := grpc.NewClient("passthrough:///"+net.JoinHostPort(host, port),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithStatsHandler(otelgrpc.NewClientHandler()),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	g := &Gateway{
+		conn:   conn,
+		client: favorite.NewFavoriteServiceClient(conn),
+		mux:    http.NewServeMux(),
+	}
+	g.routes()
+
+	return g, nil
+}
+
+func (g *Gateway) routes() {
+	g.mux.HandleFunc("POST /v1/favorites", g.action(constants.FavoriteActionType))
+	g.mux.HandleFunc("DELETE /v1/favorites", g.action(constants.UnFavoriteActionType))
+	g.mux.HandleFunc("GET /v1/favorites/status", handle(func(ctx context.Context, req *favorite.IsFavoriteRequest, opts ...grpc.CallOption) (*favorite.IsFavoriteResponse, error) {
+		return g.client.IsFavorite(ctx, req, opts...)
+	}))
+	g.mux.HandleFunc("POST /v1/favorites/status/batch", handle(func(ctx context.Context, req *favorite.BatchIsFavoriteRequest, opts ...grpc.CallOption) (*favorite.BatchIsFavoriteResponse, error) {
+		return g.client.BatchIsFavorite(ctx, req, opts...)
+	}))
+	g.mux.HandleFunc("GET /v1/favorites/count", handle(func(ctx context.Context, req *favorite.FavoriteCountRequest, opts ...grpc.CallOption) (*favorite.FavoriteCountResponse, error) {
+		return g.client.FavoriteCount(ctx, req, opts...)
+	}))
+	g.mux.HandleFunc("POST /v1/favorites/count/batch", handle(func(ctx context.Context, req *favorite.BatchFavoriteCountRequest, opts ...grpc.CallOption) (*favorite.BatchFavoriteCountResponse, error) {
+		return g.client.BatchFavoriteCount(ctx, req, opts...)
+	}))
+	g.mux.HandleFunc("GET /v1/favorites/count/history", handle(func(ctx context.Context, req *favorite.FavoriteCountHistoryRequest, opts ...grpc.CallOption) (*favorite.FavoriteCountHistoryResponse, error) {
+		return g.client.FavoriteCountHistory(ctx, req, opts...)
+	}))
+	g.mux.HandleFunc("GET /v1/favorites/likers", handle(func(ctx context.Context, req *favorite.BizFavoriteUserRequest, opts ...grpc.CallOption) (*favorite.BizFavoriteUserResponse, error) {
+		return g.client.BizFavoriteUser(ctx, req, opts...)
+	}))
+	g.mux.HandleFunc("GET /v1/favorites/friends", handle(func(ctx context.Context, req *favorite.FriendsFavoritedRequest, opts ...grpc.CallOption) (*favorite.FriendsFavoritedResponse, error) {
+		return g.client.FriendsFavorited(ctx, req, opts...)
+	}))
+	g.mux.HandleFunc("GET /v1/favorites/related", handle(func(ctx context.Context, req *favorite.RelatedContentRequest, opts ...grpc.CallOption) (*favorite.RelatedContentResponse, error) {
+		return g.client.RelatedContent(ctx, req, opts...)
+	}))
+	g.mux.HandleFunc("GET /v1/users/{user_id}/favorites", handle(func(ctx context.Context, req *favorite.FavoriteListRequest, opts ...grpc.CallOption) (*favorite.FavoriteListResponse, error) {
+		return g.client.FavoriteList(ctx, req, opts...)
+	}))
+	g.mux.HandleFunc("GET /v1/users/{user_id}/favorites/count", handle(func(ctx context.Context, req *favorite.UserFavoriteCountRequest, opts ...grpc.CallOption) (*favorite.UserFavoriteCountResponse, error) {
+		return g.client.UserFavoriteCount(ctx, req, opts...)
+	}))
+	g.mux.HandleFunc("GET /v1/creators/{owner_id}/favorited/count", handle(func(ctx context.Context, req *favorite.CreatorReceivedCountRequest, opts ...grpc.CallOption) (*favorite.CreatorReceivedCountResponse, error) {
+		return g.client.CreatorReceivedCount(ctx, req, opts...)
+	}))
+	g.mux.HandleFunc("GET /v1/favorited/count", handle(func(ctx context.Context, req *favorite.UserFavoritedCountRequest, opts ...grpc.CallOption) (*favorite.UserFavoritedCountResponse, error) {
+		return g.client.UserFavoritedCount(ctx, req, opts...)
+	}))
+}
+
+func (g *Gateway) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	g.mux.ServeHTTP(w, r)
+}
+
+func (g *Gateway) Close() error {
+	return g.conn.Close()
+}
+
+// action 点赞/取消点赞, action_type 由 HTTP 方法决定
+func (g *Gateway) action(actionType int32) http.HandlerFunc {
+	return handle(func(ctx context.Context, req *favorite.FavoriteActionRequest, opts ...grpc.CallOption) (*favorite.FavoriteActionResponse, error) {
+		req.ActionType = actionType
+		return g.client.FavoriteAction(ctx, req, opts...)
+	})
+}
+
+// handle 解析请求 -> 调用 gRPC -> 返回 JSON, GET/DELETE 从 query 和路径参数中读取字段, 其余方法读取 JSON 请求体
+func handle[Req, Resp proto.Message](call func(context.Context, Req, ...grpc.CallOption) (Resp, error)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req Req
+		req = req.ProtoReflect().Type().New().Interface().(Req)
+
+		if err := decodeRequest(r, req); err != nil {
+			writeError(w, status.Error(codes.InvalidArgument, err.Error()))
+			return
+		}
+
+		var header metadata.MD
+		resp, err := call(outgoingContext(r), req, grpc.Header(&header))
+		if v := header.Get("retry-after"); len(v) > 0 {
+			w.Header().Set("Retry-After", v[0])
+		}
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+
+		body, err := gatewayMarshaler.Marshal(resp)
+		if err != nil {
+			writeError(w, status.Error(codes.Internal, err.Error()))
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write(body)
+	}
+}
+
+func decodeRequest(r *http.Request, msg proto.Message) error {
+	if r.Method != http.MethodGet && r.Method != http.MethodDelete {
+		body, err := io.ReadAll(io.LimitReader(r.Body, maxGatewayBody))
+		if err != nil {
+			return err
+		}
+		if len(body) > 0 {
+			if err := gatewayUnmarshaler.Unmarshal(body, msg); err != nil {
+				return err
+			}
+		}
+	}
+
+	m := msg.ProtoReflect()
+	fields := m.Descriptor().Fields()
+	for i := 0; i < fields.Len(); i++ {
+		fd := fields.Get(i)
+		name := string(fd.Name())
+
+		vals := r.URL.Query()[name]
+		if v := r.PathValue(name); v != "" {
+			vals = []string{v}
+		}
+		if len(vals) == 0 {
+			continue
+		}
+
+		if fd.IsList() {
+			list := m.Mutable(fd).List()
+			for _, raw := range vals {
+				for _, s := range strings.Split(raw, ",") {
+					v, err := parseScalar(fd, s)
+					if err != nil {
+						return err
+					}
+					list.Append(v)
+				}
+			}
+			continue
+		}
+
+		v, err := parseScalar(fd, vals[0])
+		if err != nil {
+			return err
+		}
+		m.Set(fd, v)
+	}
+
+	return nil
+}
+
+func parseScalar(fd protoreflect.FieldDescriptor, s string) (protoreflect.Value, error) {
+	switch fd.Kind() {
+	case protoreflect.StringKind:
+		return protoreflect.ValueOfString(s), nil
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+		v, err := strconv.ParseInt(s, 10, 64)
+		if err != nil {
+			return protoreflect.Value{}, fmt.Errorf("invalid %s: %q", fd.Name(), s)
+		}
+		return protoreflect.ValueOfInt64(v), nil
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind:
+		v, err := strconv.ParseInt(s, 10, 32)
+		if err != nil {
+			return protoreflect.Value{}, fmt.Errorf("invalid %s: %q", fd.Name(), s)
+		}
+		return protoreflect.ValueOfInt32(int32(v)), nil
+	case protoreflect.BoolKind:
+		v, err := strconv.ParseBool(s)
+		if err != nil {
+			return protoreflect.Value{}, fmt.Errorf("invalid %s: %q", fd.Name(), s)
+		}
+		return protoreflect.ValueOfBool(v), nil
+	default:
+		return protoreflect.Value{}, fmt.Errorf("unsupported query parameter %s", fd.Name())
+	}
+}
+
+// outgoingContext 透传鉴权信息与客户端地址, 使 gRPC 侧的拦截器和审计日志看到真实的调用方;
+// 网关直接面向客户端, 客户端自带的 X-Forwarded-For 等头不可信, 不做透传
+func outgoingContext(r *http.Request) context.Context {
+	md := metadata.MD{}
+	if v := r.Header.Get("Authorization"); v != "" {
+		md.Set("authorization", v)
+	}
+
+	clientIP, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		clientIP = r.RemoteAddr
+	}
+	md.Set("x-forwarded-for", clientIP)
+
+	return metadata.NewOutgoingContext(r.Context(), md)
+}
+
+type gatewayError struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+func writeError(w http.ResponseWriter, err error) {
+	st := status.Convert(err)
+
+	body, _ := json.Marshal(gatewayError{Code: st.Code().String(), Message: st.Message()})
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(httpStatusFromCode(st.Code()))
+	_, _ = w.Write(body)
+}
+
+// httpStatusFromCode gRPC 错误码到 HTTP 状态码的映射, 与 grpc-gateway 保持一致
+func httpStatusFromCode(code codes.Code) int {
+	switch code {
+	case codes.OK:
+		return http.StatusOK
+	case codes.Canceled:
+		return 499
+	case codes.InvalidArgument, codes.OutOfRange:
+		return http.StatusBadRequest
+	case codes.DeadlineExceeded:
+		return http.StatusGatewayTimeout
+	case codes.NotFound:
+		return http.StatusNotFound
+	case codes.AlreadyExists, codes.Aborted:
+		return http.StatusConflict
+	case codes.PermissionDenied:
+		return http.StatusForbidden
+	case codes.Unauthenticated:
+		return http.StatusUnauthorized
+	case codes.ResourceExhausted:
+		return http.StatusTooManyRequests
+	case codes.FailedPrecondition:
+		return http.StatusBadRequest
+	case codes.Unimplemented:
+		return http.StatusNotImplemented
+	case codes.Unavailable:
+		return http.StatusServiceUnavailable
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,69 @@
+package rpc
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/metadata"
+
+	"github.com/crazyfrankie/favorite/api/rpc_gen/favorite"
+)
+
+func TestDecodeRequestQuery(t *testing.T) {
+	r := httptest.NewRequest(http.MethodGet, "/v1/favorited/count?biz=post&biz_id=1,2&biz_id=3&unknown=x", nil)
+
+	var req favorite.UserFavoritedCountRequest
+	require.NoError(t, decodeRequest(r, &req))
+	assert.Equal(t, "post", req.GetBiz())
+	assert.Equal(t, []int64{1, 2, 3}, req.GetBizId())
+}
+
+func TestDecodeRequestPathValue(t *testing.T) {
+	r := httptest.NewRequest(http.MethodGet, "/v1/users/42/favorites?user_id=7", nil)
+	r.SetPathValue("user_id", "42")
+
+	// 路径参数优先于 query
+	var req favorite.FavoriteListRequest
+	require.NoError(t, decodeRequest(r, &req))
+	assert.Equal(t, int64(42), req.GetUserId())
+}
+
+func TestDecodeRequestBody(t *testing.T) {
+	r := httptest.NewRequest(http.MethodPost, "/v1/favorites",
+		strings.NewReader(`{"biz":"post","biz_id":"1","unknown":true}`))
+
+	var req favorite.FavoriteActionRequest
+	require.NoError(t, decodeRequest(r, &req))
+	assert.Equal(t, "post", req.GetBiz())
+	assert.Equal(t, int64(1), req.GetBizId())
+}
+
+func TestDecodeRequestInvalid(t *testing.T) {
+	r := httptest.NewRequest(http.MethodGet, "/v1/favorited/count?biz=post&biz_id=1,x", nil)
+	var req favorite.UserFavoritedCountRequest
+	assert.Error(t, decodeRequest(r, &req))
+
+	r = httptest.NewRequest(http.MethodPost, "/v1/favorites", strings.NewReader(`{"biz":`))
+	var action favorite.FavoriteActionRequest
+	assert.Error(t, decodeRequest(r, &action))
+}
+
+func TestOutgoingContext(t *testing.T) {
+	r := httptest.NewRequest(http.MethodGet, "/v1/favorites/count", nil)
+	r.RemoteAddr = "10.0.0.1:1234"
+	r.Header.Set("Authorization", "Bearer token")
+	r.Header.Set("X-Forwarded-For", "1.2.3.4")
+	r.Header.Set("X-User-Tier", "vip")
+
+	md, ok := metadata.FromOutgoingContext(outgoingContext(r))
+	require.True(t, ok)
+	// 只转发鉴权信息, 客户端 IP 取自连接地址, 不信任客户端传入的请求头
+	assert.Equal(t, metadata.MD{
+		"authorization":   {"Bearer token"},
+		"x-forwarded-for": {"10.0.0.1"},
+	}, md)
+}
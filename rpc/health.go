@@ -0,0 +1,101 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+
+	"github.com/crazyfrankie/favorite/api/rpc_gen/favorite"
+)
+
+var (
+	errShuttingDown  = errors.New("server is shutting down")
+	errNotRegistered = errors.New("service is not registered to etcd")
+)
+
+// Probe 依赖项探活
+type Probe struct {
+	Name  string
+	Check func(ctx context.Context) error
+}
+
+type Probes []Probe
+
+// Ready 服务是否可以接收流量: 未处于关闭流程, 依赖项均可用且已注册到 etcd
+func (s *Server) Ready(ctx context.Context) error {
+	if s.shuttingDown.Load() {
+		return errShuttingDown
+	}
+	for _, p := range s.probes {
+		if err := p.Check(ctx); err != nil {
+			return fmt.Errorf("%s: %w", p.Name, err)
+		}
+	}
+	if !s.registry.Registered() {
+		return errNotRegistered
+	}
+
+	return nil
+}
+
+// HealthzHandler 存活探针, 进程能响应即视为存活
+func (s *Server) HealthzHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte("ok"))
+	})
+}
+
+// ReadyzHandler 就绪探针
+func (s *Server) ReadyzHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(r.Context(), 2*time.Second)
+		defer cancel()
+
+		if err := s.Ready(ctx); err != nil {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			_, _ = w.Write([]byte(err.Error()))
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte("ok"))
+	})
+}
+
+// watchHealth 定期根据就绪状态更新 gRPC 健康检查结果
+func (s *Server) watchHealth(ctx context.Context, hs *health.Server) {
+	ticker := time.NewTicker(5 * time.Second)
+	defer ticker.Stop()
+
+	for {
+		s.updateHealth(ctx, hs)
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (s *Server) updateHealth(ctx context.Context, hs *health.Server) {
+	// 关闭流程中由 Shutdown 统一置为 NOT_SERVING
+	if s.shuttingDown.Load() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	st := healthpb.HealthCheckResponse_SERVING
+	if err := s.Ready(ctx); err != nil {
+		st = healthpb.HealthCheckResponse_NOT_SERVING
+	}
+	hs.SetServingStatus("", st)
+	hs.SetServingStatus(favorite.FavoriteService_ServiceDesc.ServiceName, st)
+}
@@ -0,0 +1,50 @@
+package rpc
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+
+	"github.com/crazyfrankie/favorite/api/rpc_gen/favorite"
+	"github.com/crazyfrankie/favorite/internal/config"
+	"github.com/crazyfrankie/favorite/pkg/auth"
+	"github.com/crazyfrankie/favorite/pkg/ratelimit"
+)
+
+type recordLimiter struct {
+	keys []ratelimit.Key
+}
+
+func (l *recordLimiter) Limit(ctx context.Context, keys ...ratelimit.Key) (int, time.Duration, error) {
+	l.keys = keys
+	return -1, 0, nil
+}
+
+func TestRateLimitKeyedByCaller(t *testing.T) {
+	l := &recordLimiter{}
+	conf := config.RateLimit{
+		Enabled: true,
+		Tiers:   map[string]config.Limit{defaultTier: {Window: time.Minute, Rate: 10}},
+	}
+	interceptor := rateLimitInterceptor(l, func() config.RateLimit { return conf })
+	handler := func(ctx context.Context, req any) (any, error) { return nil, nil }
+	info := &grpc.UnaryServerInfo{FullMethod: favorite.FavoriteService_FavoriteAction_FullMethodName}
+
+	// 请求中的 user_id 由客户端控制, 用户维度按 token 中的调用方计数
+	ctx := auth.WithCaller(context.Background(), auth.Identity{UserId: 1})
+	_, err := interceptor(ctx, &favorite.FavoriteActionRequest{UserId: 2, Biz: "post", BizId: 3}, info, handler)
+	require.NoError(t, err)
+	require.Len(t, l.keys, 2)
+	assert.Equal(t, "limit:favorite:user:1", l.keys[0].Key)
+
+	// 配置每次请求时读取, 热更新后立即生效
+	conf.Enabled = false
+	l.keys = nil
+	_, err = interceptor(ctx, &favorite.FavoriteActionRequest{UserId: 1, Biz: "post", BizId: 3}, info, handler)
+	require.NoError(t, err)
+	assert.Nil(t, l.keys)
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"net"
+	"sync/atomic"
 	"time"
 
 	grpcprom "github.com/grpc-ecosystem/go-grpc-middleware/providers/prometheus"
@@ -20,11 +21,15 @@ import (
 	oteltrace "go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
 	"google.golang.org/grpc/reflection"
 
 	"github.com/crazyfrankie/favorite/api/rpc_gen/favorite"
+	"github.com/crazyfrankie/favorite/internal/biz/metrics"
 	"github.com/crazyfrankie/favorite/internal/biz/service"
 	"github.com/crazyfrankie/favorite/internal/config"
+	"github.com/crazyfrankie/favorite/pkg/ratelimit"
 	"github.com/crazyfrankie/favorite/pkg/registry"
 )
 
@@ -36,9 +41,16 @@ type Server struct {
 	*grpc.Server
 	Port     string
 	registry *registry.ServiceRegistry
+	health   *health.Server
+	probes   Probes
+
+	shuttingDown atomic.Bool
+	// 健康检查协程的生命周期, 在 NewServer 中创建, Serve 与 Shutdown 可并发调用
+	healthCtx  context.Context
+	stopHealth context.CancelFunc
 }
 
-func NewServer(client *clientv3.Client, svc *service.FavoriteServer) *Server {
+func NewServer(client *clientv3.Client, svc *service.FavoriteServer, limiter ratelimit.Limiter, probes Probes) *Server {
 	logger, err := zap.NewProduction()
 	if err != nil {
 		panic(err)
@@ -53,7 +65,8 @@ func NewServer(client *clientv3.Client, svc *service.FavoriteServer) *Server {
 	}
 
 	favoriteMetrics := grpcprom.NewServerMetrics()
-	PromRegistry.MustRegister(favoriteMetrics)
+	PromRegistry.MustRegister(favoriteMetrics, throttledCounter)
+	PromRegistry.MustRegister(metrics.Collectors()...)
 
 	labelsFromContext := func(ctx context.Context) prometheus.Labels {
 		if span := oteltrace.SpanContextFromContext(ctx); span.IsSampled() {
@@ -74,9 +87,20 @@ func NewServer(client *clientv3.Client, svc *service.FavoriteServer) *Server {
 		grpc.ChainUnaryInterceptor(
 			favoriteMetrics.UnaryServerInterceptor(grpcprom.WithExemplarFromContext(labelsFromContext)),
 			logging.UnaryServerInterceptor(interceptorLogger(logger), logging.WithFieldsFromContext(traceId)),
+			authInterceptor([]byte(config.GetConf().JWT.SecretKey)),
+			rateLimitInterceptor(limiter, func() config.RateLimit {
+				return config.GetConf().RateLimit
+			}),
+		),
+		grpc.ChainStreamInterceptor(
+			favoriteMetrics.StreamServerInterceptor(grpcprom.WithExemplarFromContext(labelsFromContext)),
+			logging.StreamServerInterceptor(interceptorLogger(logger), logging.WithFieldsFromContext(traceId)),
+			authStreamInterceptor([]byte(config.GetConf().JWT.SecretKey)),
 		),
 	)
 	reflection.Register(s)
+	hs := health.NewServer()
+	healthpb.RegisterHealthServer(s, hs)
 	favorite.RegisterFavoriteServiceServer(s, svc)
 	favoriteMetrics.InitializeMetrics(s)
 
@@ -85,10 +109,16 @@ func NewServer(client *clientv3.Client, svc *service.FavoriteServer) *Server {
 		panic(err)
 	}
 
+	healthCtx, stopHealth := context.WithCancel(context.Background())
+
 	return &Server{
-		Server:   s,
-		Port:     config.GetConf().Server.Port,
-		registry: rgy,
+		Server:     s,
+		Port:       config.GetConf().Server.Port,
+		registry:   rgy,
+		health:     hs,
+		probes:     probes,
+		healthCtx:  healthCtx,
+		stopHealth: stopHealth,
 	}
 }
 
@@ -103,10 +133,17 @@ func (s *Server) Serve() error {
 		return err
 	}
 
+	go s.watchHealth(s.healthCtx, s.health)
+
 	return s.Server.Serve(conn)
 }
 
 func (s *Server) Shutdown() {
+	// 先摘除流量, 再注销服务
+	s.shuttingDown.Store(true)
+	s.stopHealth()
+	s.health.Shutdown()
+
 	err := s.registry.UnRegister()
 	if err != nil {
 		zap.L().Error("Failed to unregister", zap.Error(err))